@@ -0,0 +1,155 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	AccountsURI        string = "/api/v1/accounts/%s"
+	AccountsLookupURI  string = "/api/v1/accounts/lookup"
+	AccountStatusesURI string = "/api/v1/accounts/%s/statuses"
+)
+
+// Account hold information for an account
+type Account struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	Acct           string    `json:"acct"`
+	DisplayName    string    `json:"display_name"`
+	Locked         bool      `json:"locked"`
+	Bot            bool      `json:"bot"`
+	Discoverable   bool      `json:"discoverable"`
+	Group          bool      `json:"group"`
+	CreatedAt      time.Time `json:"created_at"`
+	Note           string    `json:"note"`
+	URL            string    `json:"url"`
+	Avatar         string    `json:"avatar"`
+	AvatarStatic   string    `json:"avatar_static"`
+	Header         string    `json:"header"`
+	HeaderStatic   string    `json:"header_static"`
+	FollowersCount int       `json:"followers_count"`
+	FollowingCount int       `json:"following_count"`
+	StatusesCount  int       `json:"statuses_count"`
+	LastStatusAt   string    `json:"last_status_at"`
+	Noindex        bool      `json:"noindex"`
+	Emojis         []Emoji   `json:"emojis"`
+	Fields         []struct {
+		Name       string      `json:"name"`
+		Value      string      `json:"value"`
+		VerifiedAt interface{} `json:"verified_at"`
+	} `json:"fields"`
+}
+
+// Accounts hold a list of accounts
+type Accounts []Account
+
+// AccountStatusesParams hold the filters for account statuses requests
+type AccountStatusesParams struct {
+	PageParams
+	ExcludeReplies bool
+	ExcludeReblogs bool
+	OnlyMedia      bool
+	Pinned         bool
+	Tagged         string
+}
+
+// Add the account statuses parameters to the query values
+func (p *AccountStatusesParams) encode(v url.Values) {
+	if p == nil {
+		return
+	}
+	if p.ExcludeReplies {
+		v.Set("exclude_replies", "true")
+	}
+	if p.ExcludeReblogs {
+		v.Set("exclude_reblogs", "true")
+	}
+	if p.OnlyMedia {
+		v.Set("only_media", "true")
+	}
+	if p.Pinned {
+		v.Set("pinned", "true")
+	}
+	if p.Tagged != "" {
+		v.Set("tagged", strings.TrimPrefix(p.Tagged, "#"))
+	}
+	p.PageParams.encode(v)
+}
+
+// Get information about the account with the given ID
+func (c *Client) GetAccount(id string) (Account, error) {
+	return c.GetAccountContext(context.Background(), id)
+}
+
+// Same as GetAccount but the requests use the given context
+func (c *Client) GetAccountContext(ctx context.Context, id string) (Account, error) {
+	account := Account{}
+
+	uri := fmt.Sprintf(AccountsURI, url.PathEscape(id))
+	endpoint := c.buildURL(uri, nil)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return account, err
+	}
+
+	err = json.Unmarshal(body, &account)
+
+	return account, err
+}
+
+// Get the account for a webfinger address such as user or user@domain
+func (c *Client) LookupAccount(acct string) (Account, error) {
+	return c.LookupAccountContext(context.Background(), acct)
+}
+
+// Same as LookupAccount but the requests use the given context
+func (c *Client) LookupAccountContext(ctx context.Context, acct string) (Account, error) {
+	account := Account{}
+
+	if err := c.require(FeatureAccountLookup); err != nil {
+		return account, err
+	}
+
+	v := url.Values{}
+	v.Set("acct", strings.TrimPrefix(acct, "@"))
+	endpoint := c.buildURL(AccountsLookupURI, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return account, err
+	}
+
+	err = json.Unmarshal(body, &account)
+
+	return account, err
+}
+
+// Get statuses posted by the account with the given ID. The params are optional and may be nil
+func (c *Client) GetAccountStatuses(id string, params *AccountStatusesParams) (Statuses, error) {
+	return c.GetAccountStatusesContext(context.Background(), id, params)
+}
+
+// Same as GetAccountStatuses but the requests use the given context
+func (c *Client) GetAccountStatusesContext(ctx context.Context, id string, params *AccountStatusesParams) (Statuses, error) {
+	statuses := Statuses{}
+
+	v := url.Values{}
+	params.encode(v)
+	uri := fmt.Sprintf(AccountStatusesURI, url.PathEscape(id))
+	endpoint := c.buildURL(uri, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return statuses, err
+	}
+
+	err = json.Unmarshal(body, &statuses)
+
+	return statuses, err
+}
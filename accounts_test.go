@@ -0,0 +1,170 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testaccount string = `{
+		"id": "1",
+		"username": "Gargron",
+		"acct": "Gargron",
+		"display_name": "Eugen 💀",
+		"locked": false,
+		"bot": false,
+		"discoverable": true,
+		"group": false,
+		"created_at": "2016-03-16T00:00:00.000Z",
+		"note": "<p>Founder, CEO and lead developer of Mastodon.</p>",
+		"url": "https://mastodon.social/@Gargron",
+		"avatar": "https://files.mastodon.social/accounts/avatars/000/000/001/original/dc4286ceb8fab734.jpg",
+		"avatar_static": "https://files.mastodon.social/accounts/avatars/000/000/001/original/dc4286ceb8fab734.jpg",
+		"header": "https://files.mastodon.social/accounts/headers/000/000/001/original/3b91c9965d00888b.jpeg",
+		"header_static": "https://files.mastodon.social/accounts/headers/000/000/001/original/3b91c9965d00888b.jpeg",
+		"followers_count": 133026,
+		"following_count": 311,
+		"statuses_count": 72605,
+		"last_status_at": "2022-10-31",
+		"noindex": false,
+		"emojis": [],
+		"fields": [
+		  {
+			"name": "Patreon",
+			"value": "<a href=\"https://www.patreon.com/mastodon\">patreon.com/mastodon</a>",
+			"verified_at": null
+		  }
+		]
+	  }`
+)
+
+func TestGetAccount(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(AccountsURI, "1"):
+			fmt.Fprintln(w, testaccount)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	account, err := client.GetAccount("1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if account.Username != "Gargron" {
+		t.Fatalf("username was incorrectly set to: %s", account.Username)
+	}
+
+	_, err = client.GetAccount("2")
+	if err == nil {
+		t.Fatalf("should fail for an unknown account")
+	}
+}
+
+func TestLookupAccount(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case AccountsLookupURI:
+			if r.URL.Query().Get("acct") != "Gargron@mastodon.social" {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintln(w, testaccount)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	account, err := client.LookupAccount("@Gargron@mastodon.social")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if account.ID != "1" {
+		t.Fatalf("id was incorrectly set to: %s", account.ID)
+	}
+}
+
+func TestGetAccountStatuses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup Statuses
+		var statuses Statuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(AccountStatusesURI, "13179"):
+			q := r.URL.Query()
+			if q.Get("exclude_replies") != "true" || q.Get("exclude_reblogs") != "true" {
+				t.Errorf("invalid exclude filters: %s", r.URL.RawQuery)
+			}
+			if q.Get("pinned") != "" || q.Get("only_media") != "" {
+				t.Errorf("unexpected filters: %s", r.URL.RawQuery)
+			}
+			if q.Get("tagged") != "mastodon" || q.Get("limit") != "5" {
+				t.Errorf("invalid tagged or limit: %s", r.URL.RawQuery)
+			}
+
+			body, err := json.Marshal(statuses)
+			if err != nil {
+				t.Fatalf("error marshalling test statuses: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	params := &AccountStatusesParams{
+		PageParams:     PageParams{Limit: 5},
+		ExcludeReplies: true,
+		ExcludeReblogs: true,
+		Tagged:         "#mastodon",
+	}
+
+	statuses, err := client.GetAccountStatuses("13179", params)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(statuses) != 1 {
+		t.Fatalf("should have returned 1 status but instead returned: %d", len(statuses))
+	}
+}
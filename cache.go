@@ -0,0 +1,223 @@
+package mastodon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry hold a cached response and the validators used to revalidate it
+type CacheEntry struct {
+	Body         []byte      `json:"body"`
+	Header       http.Header `json:"header"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Expires      time.Time   `json:"expires"`
+}
+
+// Fresh reports whether the entry can be used without asking the server
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// Cache stores response bodies keyed by request URL
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry) error
+	Delete(key string) error
+}
+
+// MemoryCache is a Cache that keeps entries in memory
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+}
+
+// NewMemoryCache returns an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: map[string]*CacheEntry{},
+	}
+}
+
+// Get a copy of the entry stored for the key
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	copied := *entry
+
+	return &copied, true
+}
+
+// Store the entry for the key
+func (m *MemoryCache) Set(key string, entry *CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry
+
+	return nil
+}
+
+// Remove the entry stored for the key
+func (m *MemoryCache) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+// DiskCache is a Cache that stores each entry as a JSON file in a directory
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a cache that stores entries in the directory, which is
+// created if it does not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if dir == "" {
+		return nil, errors.New("cache directory can not be empty")
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get the path of the file for the key
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get the entry stored for the key. Unreadable files are treated as missing
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := &CacheEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// Store the entry for the key, replacing the file atomically
+func (d *DiskCache) Set(key string, entry *CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeerr := tmp.Close(); err == nil {
+		err = closeerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+// Remove the entry stored for the key
+func (d *DiskCache) Delete(key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Create a cache entry from a 200 response. Returns false if the response
+// must not be stored or can never be reused
+func newCacheEntry(body []byte, header http.Header, now time.Time) (*CacheEntry, bool) {
+	entry := &CacheEntry{
+		Body:         body,
+		Header:       header,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+
+	maxage, store := parseCacheControl(header.Get("Cache-Control"))
+	if !store {
+		return nil, false
+	}
+	if maxage > 0 {
+		entry.Expires = now.Add(maxage)
+	}
+
+	if entry.Expires.IsZero() && entry.ETag == "" && entry.LastModified == "" {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// Parse the max-age of the Cache-Control header. Returns false for no-store
+func parseCacheControl(value string) (time.Duration, bool) {
+	var maxage time.Duration
+
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-store":
+			return 0, false
+		case directive == "no-cache":
+			// Always revalidate
+			return 0, true
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds > 0 {
+				maxage = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return maxage, true
+}
+
+// WithCache stores responses in the cache, reusing them while they are fresh
+// according to Cache-Control max-age and revalidating them with
+// If-None-Match and If-Modified-Since once they are stale
+func WithCache(cache Cache) Option {
+	return func(c *Client) error {
+		if cache == nil {
+			return errors.New("cache can not be nil")
+		}
+		c.cache = cache
+
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	requests := map[string]int{}
+	revalidated := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		// Return based on URI
+		switch r.URL.Path {
+		case CustomEmojisURI:
+			// Fresh for an hour
+			w.Header().Set("Cache-Control", "max-age=3600, public")
+			fmt.Fprintln(w, "[]")
+			return
+		case InstanceRulesURI:
+			// Always revalidate
+			if r.Header.Get("If-None-Match") == `W/"rules-1"` {
+				revalidated++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `W/"rules-1"`)
+			fmt.Fprintln(w, testinstancerules)
+			return
+		case InstancePeersURI:
+			w.Header().Set("Cache-Control", "no-store")
+			fmt.Fprintln(w, "[]")
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	caches := map[string]func() Cache{
+		"memory": func() Cache { return NewMemoryCache() },
+		"disk": func() Cache {
+			cache, err := NewDiskCache(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create disk cache: %v", err)
+			}
+			return cache
+		},
+	}
+
+	for name, newCache := range caches {
+		requests = map[string]int{}
+		revalidated = 0
+
+		// Setup client
+		client, err := NewClient(ts.URL, WithCache(newCache()))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			if _, err := client.GetCustomEmojis(); err != nil {
+				t.Fatalf("%s: should not be fail: %v", name, err)
+			}
+
+			rules, err := client.GetInstanceRules()
+			if err != nil {
+				t.Fatalf("%s: should not be fail: %v", name, err)
+			}
+			if len(rules) != 6 {
+				t.Fatalf("%s: should have returned 6 cached rules but instead returned: %d", name, len(rules))
+			}
+
+			if _, err := client.GetInstancePeers(); err != nil {
+				t.Fatalf("%s: should not be fail: %v", name, err)
+			}
+		}
+
+		if requests[CustomEmojisURI] != 1 {
+			t.Fatalf("%s: fresh entry should be reused but sent %d requests", name, requests[CustomEmojisURI])
+		}
+		if requests[InstanceRulesURI] != 3 || revalidated != 2 {
+			t.Fatalf("%s: entry should be revalidated but sent %d requests and revalidated %d", name, requests[InstanceRulesURI], revalidated)
+		}
+		if requests[InstancePeersURI] != 3 {
+			t.Fatalf("%s: no-store should not be cached but sent %d requests", name, requests[InstancePeersURI])
+		}
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	maxage, store := parseCacheControl("public, max-age=180")
+	if !store || maxage != 180*time.Second {
+		t.Fatalf("max-age was incorrectly parsed: %s", maxage)
+	}
+
+	_, store = parseCacheControl("private, no-store")
+	if store {
+		t.Fatalf("no-store should not be stored")
+	}
+
+	maxage, store = parseCacheControl("no-cache, max-age=60")
+	if !store || maxage != 0 {
+		t.Fatalf("no-cache should always be revalidated: %s", maxage)
+	}
+}
+
+func TestCacheConcurrentRevalidation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"emojis"`)
+		if r.Header.Get("If-None-Match") == `"emojis"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, WithCache(NewMemoryCache()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.GetCustomEmojis(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// Revalidating the same entry from many goroutines must not race
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetCustomEmojis(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("should not be fail: %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CrawlNode hold what the crawler learned about a single instance
+type CrawlNode struct {
+	Domain string
+	Depth  int
+	// Nil when the instance data could not be fetched
+	Instance    *Instance
+	InstanceErr error
+	Peers       InstancePeers
+	PeersErr    error
+}
+
+// Reachable reports whether the instance data could be fetched
+func (n CrawlNode) Reachable() bool {
+	return n.Instance != nil
+}
+
+// CrawlSink receives every instance visited by the crawler. Sinks are called
+// from a single goroutine and returning an error stops the crawl
+type CrawlSink interface {
+	Discovered(ctx context.Context, node CrawlNode) error
+}
+
+// CrawlSinkFunc is a function that can be used as a CrawlSink
+type CrawlSinkFunc func(ctx context.Context, node CrawlNode) error
+
+// Discovered calls the function
+func (f CrawlSinkFunc) Discovered(ctx context.Context, node CrawlNode) error {
+	return f(ctx, node)
+}
+
+// CrawlerConfig hold the settings of a Crawler
+type CrawlerConfig struct {
+	// Domains or server URLs to start from. Domains and discovered peers are
+	// visited over https, server URLs keep their scheme and port
+	Seeds []string
+	// Number of peer hops to follow from the seeds, 0 only visits the seeds
+	MaxDepth int
+	// Maximum number of instances to visit, 0 for no limit
+	MaxInstances int
+	// Only visit domains matching one of these, all domains when empty.
+	// A pattern matches the domain itself and all of its subdomains, and a
+	// pattern starting with *. only matches the subdomains
+	Allow []string
+	// Never visit domains matching one of these, checked after Allow
+	Deny []string
+	// Politeness delay between requests to the same host, including retries
+	Delay time.Duration
+	// Number of instances visited at the same time, PoolConcurrency when zero
+	Concurrency int
+	// Time limit for visiting a single instance, no limit when zero
+	HostTimeout time.Duration
+	// Receives every visited instance
+	Sink CrawlSink
+	// Options used to create the client of every instance
+	Options []Option
+}
+
+// Crawler maps the fediverse by following instance peers breadth-first
+type Crawler struct {
+	config CrawlerConfig
+}
+
+// NewCrawler returns a crawler for the config
+func NewCrawler(config CrawlerConfig) (*Crawler, error) {
+	if len(config.Seeds) == 0 {
+		return nil, errors.New("crawler needs at least one seed")
+	}
+	if config.Sink == nil {
+		return nil, errors.New("crawler sink can not be nil")
+	}
+	if config.MaxDepth < 0 || config.MaxInstances < 0 || config.Delay < 0 {
+		return nil, errors.New("crawler depth, instances and delay can not be negative")
+	}
+
+	return &Crawler{config: config}, nil
+}
+
+// Run visits the seeds and then their peers, level by level, until MaxDepth
+// or MaxInstances is reached, the context is done or the sink returns an error
+func (cr *Crawler) Run(ctx context.Context) error {
+	seen := map[string]bool{}
+	visited := 0
+
+	// One pacer for the whole crawl so every request to a host is spaced out
+	options := append([]Option{}, cr.config.Options...)
+	if cr.config.Delay > 0 {
+		options = append(options, withPacer(newPacer(cr.config.Delay)))
+	}
+
+	// Server URL of every domain to visit
+	servers := map[string]string{}
+
+	level := []string{}
+	for _, seed := range cr.config.Seeds {
+		domain := normalizeDomain(seed)
+		if domain == "" || seen[domain] || !cr.allowed(domain) {
+			continue
+		}
+		seen[domain] = true
+		level = append(level, domain)
+
+		servers[domain] = "https://" + domain
+		if u, err := url.Parse(strings.TrimSpace(seed)); err == nil && strings.Contains(seed, "://") {
+			servers[domain] = strings.ToLower(u.Scheme) + "://" + domain
+		}
+	}
+
+	for depth := 0; len(level) > 0; depth++ {
+		if cr.config.MaxInstances > 0 {
+			remaining := cr.config.MaxInstances - visited
+			if remaining <= 0 {
+				return nil
+			}
+			if len(level) > remaining {
+				level = level[:remaining]
+			}
+		}
+
+		levelservers := make([]string, len(level))
+		for i, domain := range level {
+			levelservers[i] = servers[domain]
+		}
+
+		pool, err := NewPool(levelservers, cr.config.Concurrency, cr.config.HostTimeout, options...)
+		if err != nil {
+			return err
+		}
+
+		results := FanOut(ctx, pool, cr.visit)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		next := []string{}
+		for i, result := range results {
+			node := result.Value
+			node.Domain = level[i]
+			node.Depth = depth
+			visited++
+
+			if err := cr.config.Sink.Discovered(ctx, node); err != nil {
+				return err
+			}
+
+			if depth >= cr.config.MaxDepth {
+				continue
+			}
+
+			for _, peer := range node.Peers {
+				domain := normalizeDomain(peer)
+				if domain == "" || seen[domain] || !cr.allowed(domain) {
+					continue
+				}
+				seen[domain] = true
+				servers[domain] = "https://" + domain
+				next = append(next, domain)
+			}
+		}
+
+		level = next
+	}
+
+	return nil
+}
+
+// Fetch the instance data and peers of a single instance
+func (cr *Crawler) visit(c *Client, ctx context.Context) (CrawlNode, error) {
+	node := CrawlNode{}
+
+	instance, err := c.GetInstanceDataContext(ctx)
+	if err != nil {
+		node.InstanceErr = err
+	} else {
+		node.Instance = &instance
+	}
+
+	node.Peers, node.PeersErr = c.GetInstancePeersContext(ctx)
+
+	return node, nil
+}
+
+// Check the domain against the allow and deny lists
+func (cr *Crawler) allowed(domain string) bool {
+	if len(cr.config.Allow) > 0 && !matchDomain(domain, cr.config.Allow) {
+		return false
+	}
+
+	return !matchDomain(domain, cr.config.Deny)
+}
+
+// Report whether the domain equals or is a subdomain of one of the patterns.
+// Patterns starting with *. only match subdomains
+func matchDomain(domain string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		wildcard := strings.HasPrefix(pattern, "*.")
+		pattern = normalizeDomain(strings.TrimPrefix(pattern, "*."))
+		if pattern == "" {
+			continue
+		}
+		if (!wildcard && domain == pattern) || strings.HasSuffix(domain, "."+pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the lower case host name of a domain or URL, or an empty string if it
+// is not a valid host name
+func normalizeDomain(value string) string {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		value = u.Host
+	}
+	value = strings.TrimSuffix(value, ".")
+
+	if value == "" || len(value) > 253 {
+		return ""
+	}
+	for _, r := range value {
+		valid := r == '.' || r == '-' || r == ':' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127
+		if !valid {
+			return ""
+		}
+	}
+
+	return value
+}
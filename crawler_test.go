@@ -0,0 +1,301 @@
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Transport that sends every request to the test server, keeping the host
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = req.URL.Host
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCrawler(t *testing.T) {
+	// Setup a small fediverse
+	peers := map[string][]string{
+		"seed.example":    {"a.example", "b.example", "blocked.example", "Seed.Example"},
+		"a.example":       {"c.example", "seed.example"},
+		"b.example":       {"c.example", "sub.blocked.example"},
+		"c.example":       {"d.example"},
+		"blocked.example": {"e.example"},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		domainpeers, ok := peers[r.Host]
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case InstanceURI:
+			fmt.Fprintf(w, `{"domain": "%s", "version": "4.0.2"}`+"\n", r.Host)
+			return
+		case InstancePeersURI:
+			fmt.Fprintf(w, `["%s"]`+"\n", strings.Join(domainpeers, `", "`))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	target, _ := url.Parse(ts.URL)
+	nodes := map[string]CrawlNode{}
+
+	crawler, err := NewCrawler(CrawlerConfig{
+		Seeds:    []string{"https://seed.example/"},
+		MaxDepth: 2,
+		Deny:     []string{"blocked.example"},
+		Sink: CrawlSinkFunc(func(ctx context.Context, node CrawlNode) error {
+			if _, ok := nodes[node.Domain]; ok {
+				t.Errorf("domain visited twice: %s", node.Domain)
+			}
+			nodes[node.Domain] = node
+			return nil
+		}),
+		Options: []Option{WithTransport(&rewriteTransport{target: target})},
+	})
+	if err != nil {
+		t.Fatalf("failed to create crawler: %v", err)
+	}
+
+	err = crawler.Run(context.Background())
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	domains := []string{}
+	for domain := range nodes {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	// d.example is at depth 3 and blocked domains are never visited
+	if strings.Join(domains, ",") != "a.example,b.example,c.example,seed.example" {
+		t.Fatalf("visited the wrong domains: %v", domains)
+	}
+
+	if nodes["c.example"].Depth != 2 || !nodes["c.example"].Reachable() {
+		t.Fatalf("c.example was incorrectly recorded: %+v", nodes["c.example"])
+	}
+
+	if nodes["seed.example"].Instance.Domain != "seed.example" || len(nodes["seed.example"].Peers) != 4 {
+		t.Fatalf("seed.example was incorrectly recorded: %+v", nodes["seed.example"])
+	}
+}
+
+func TestCrawlerMatchDomain(t *testing.T) {
+	patterns := []string{"example.com", "*.social"}
+
+	for domain, expected := range map[string]bool{
+		"example.com":          true,
+		"mastodon.example.com": true,
+		"notexample.com":       false,
+		"mastodon.social":      true,
+		"social":               false,
+		"example.org":          false,
+	} {
+		if matchDomain(domain, patterns) != expected {
+			t.Fatalf("%s should match: %t", domain, expected)
+		}
+	}
+
+	if normalizeDomain("bad domain") != "" || normalizeDomain("HTTPS://Mastodon.Social/") != "mastodon.social" {
+		t.Fatalf("domains were incorrectly normalized")
+	}
+}
+
+// Serve instance data and peers for every host of the peers map, recording
+// the time of every request per host
+type crawlerServer struct {
+	mu       sync.Mutex
+	peers    map[string][]string
+	requests map[string][]time.Time
+}
+
+func (s *crawlerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.Host] = append(s.requests[r.Host], time.Now())
+	domainpeers, ok := s.peers[r.Host]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	// Return based on URI
+	switch r.URL.Path {
+	case InstanceURI:
+		fmt.Fprintf(w, `{"domain": "%s", "version": "4.0.2"}`+"\n", r.Host)
+		return
+	case InstancePeersURI:
+		fmt.Fprintf(w, `["%s"]`+"\n", strings.Join(domainpeers, `", "`))
+		return
+	}
+
+	// URI not specified above, return status not found
+	http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+}
+
+func TestCrawlerMaxInstances(t *testing.T) {
+	server := &crawlerServer{
+		peers: map[string][]string{
+			"seed.example": {"a.example", "b.example", "c.example"},
+			"a.example":    {"d.example"},
+			"b.example":    {},
+			"c.example":    {},
+		},
+		requests: map[string][]time.Time{},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	target, _ := url.Parse(ts.URL)
+	domains := []string{}
+
+	crawler, err := NewCrawler(CrawlerConfig{
+		Seeds:        []string{"seed.example"},
+		MaxDepth:     3,
+		MaxInstances: 3,
+		Sink: CrawlSinkFunc(func(ctx context.Context, node CrawlNode) error {
+			domains = append(domains, node.Domain)
+			return nil
+		}),
+		Options: []Option{WithTransport(&rewriteTransport{target: target})},
+	})
+	if err != nil {
+		t.Fatalf("failed to create crawler: %v", err)
+	}
+
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// The level of peers is cut to the remaining instances, in peer order
+	if strings.Join(domains, ",") != "seed.example,a.example,b.example" {
+		t.Fatalf("visited the wrong domains: %v", domains)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.requests) != 3 {
+		t.Fatalf("only 3 hosts should be requested: %v", server.requests)
+	}
+}
+
+func TestCrawlerDelay(t *testing.T) {
+	server := &crawlerServer{
+		peers: map[string][]string{
+			"seed.example": {"a.example"},
+			"a.example":    {"seed.example"},
+		},
+		requests: map[string][]time.Time{},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	target, _ := url.Parse(ts.URL)
+	delay := 50 * time.Millisecond
+
+	crawler, err := NewCrawler(CrawlerConfig{
+		Seeds:    []string{"seed.example"},
+		MaxDepth: 1,
+		Delay:    delay,
+		Sink: CrawlSinkFunc(func(ctx context.Context, node CrawlNode) error {
+			return nil
+		}),
+		Options: []Option{WithTransport(&rewriteTransport{target: target})},
+	})
+	if err != nil {
+		t.Fatalf("failed to create crawler: %v", err)
+	}
+
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for host, times := range server.requests {
+		if len(times) != 2 {
+			t.Fatalf("%s should get 2 requests, got %d", host, len(times))
+		}
+		for i := 1; i < len(times); i++ {
+			// Allow for the timer resolution of the platform
+			if gap := times[i].Sub(times[i-1]); gap < delay-5*time.Millisecond {
+				t.Fatalf("requests to %s were only %s apart", host, gap)
+			}
+		}
+	}
+}
+
+func TestCrawlerSeedScheme(t *testing.T) {
+	ts := httptest.NewServer(&crawlerServer{
+		peers: map[string][]string{
+			"seed.example:8080": {"a.example"},
+			"a.example":         {},
+		},
+		requests: map[string][]time.Time{},
+	})
+	defer ts.Close()
+
+	target, _ := url.Parse(ts.URL)
+	var mu sync.Mutex
+	schemes := map[string]string{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		schemes[req.URL.Host] = req.URL.Scheme
+		mu.Unlock()
+		return (&rewriteTransport{target: target}).RoundTrip(req)
+	})
+
+	crawler, err := NewCrawler(CrawlerConfig{
+		Seeds:    []string{"http://seed.example:8080"},
+		MaxDepth: 1,
+		Sink: CrawlSinkFunc(func(ctx context.Context, node CrawlNode) error {
+			return nil
+		}),
+		Options: []Option{WithTransport(transport)},
+	})
+	if err != nil {
+		t.Fatalf("failed to create crawler: %v", err)
+	}
+
+	if err := crawler.Run(context.Background()); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// Seeds keep their scheme and port, discovered peers use https
+	mu.Lock()
+	defer mu.Unlock()
+	if schemes["seed.example:8080"] != "http" || schemes["a.example"] != "https" {
+		t.Fatalf("unexpected schemes: %v", schemes)
+	}
+}
+
+// Function that can be used as a http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -1,6 +1,7 @@
 package mastodon
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -9,8 +10,8 @@ const (
 	CustomEmojisURI string = "/api/v1/custom_emojis"
 )
 
-// Emojis hold information for custom emojis
-type Emojis []struct {
+// Emoji hold information for a custom emoji
+type Emoji struct {
 	Shortcode       string `json:"shortcode"`
 	URL             string `json:"url"`
 	StaticURL       string `json:"static_url"`
@@ -18,13 +19,21 @@ type Emojis []struct {
 	Category        string `json:"category,omitempty"`
 }
 
+// Emojis hold information for custom emojis
+type Emojis []Emoji
+
 // Get custom emojis that are available on the server
 func (c *Client) GetCustomEmojis() (Emojis, error) {
+	return c.GetCustomEmojisContext(context.Background())
+}
+
+// Same as GetCustomEmojis but the requests use the given context
+func (c *Client) GetCustomEmojisContext(ctx context.Context) (Emojis, error) {
 	var customemojis Emojis
 
 	url := fmt.Sprintf("%s%s", c.Server, CustomEmojisURI)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, url)
 	if err != nil {
 		return customemojis, err
 	}
@@ -0,0 +1,121 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DirectoryURI string = "/api/v1/directory"
+
+	DirectoryOrderActive string = "active"
+	DirectoryOrderNew    string = "new"
+
+	// Default number of accounts requested per page when walking the directory
+	DirectoryPageLimit int = 40
+)
+
+// DirectoryParams hold the filters for profile directory requests
+type DirectoryParams struct {
+	Offset int
+	Limit  int
+	Order  string
+	Local  bool
+}
+
+// Add the directory parameters to the query values
+func (p *DirectoryParams) encode(v url.Values) {
+	if p == nil {
+		return
+	}
+	if p.Offset > 0 {
+		v.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Order != "" {
+		v.Set("order", p.Order)
+	}
+	if p.Local {
+		v.Set("local", "true")
+	}
+}
+
+// Get accounts visible in the profile directory. The params are optional and may be nil
+func (c *Client) GetDirectory(params *DirectoryParams) (Accounts, error) {
+	return c.GetDirectoryContext(context.Background(), params)
+}
+
+// Same as GetDirectory but the requests use the given context
+func (c *Client) GetDirectoryContext(ctx context.Context, params *DirectoryParams) (Accounts, error) {
+	accounts := Accounts{}
+
+	if err := c.require(FeatureDirectory); err != nil {
+		return accounts, err
+	}
+
+	v := url.Values{}
+	params.encode(v)
+	endpoint := c.buildURL(DirectoryURI, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return accounts, err
+	}
+
+	err = json.Unmarshal(body, &accounts)
+
+	return accounts, err
+}
+
+// Walk the whole profile directory, starting at the offset of the params, and
+// call fn once for every account. Walking stops at the first page without new
+// accounts, which includes empty pages, or when fn returns an error, which is
+// then returned. The params are optional and may be nil
+func (c *Client) WalkDirectory(params *DirectoryParams, fn func(Account) error) error {
+	return c.WalkDirectoryContext(context.Background(), params, fn)
+}
+
+// Same as WalkDirectory but the requests use the given context
+func (c *Client) WalkDirectoryContext(ctx context.Context, params *DirectoryParams, fn func(Account) error) error {
+	page := DirectoryParams{Limit: DirectoryPageLimit}
+	if params != nil {
+		page = *params
+		if page.Limit <= 0 {
+			page.Limit = DirectoryPageLimit
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		accounts, err := c.GetDirectoryContext(ctx, &page)
+		if err != nil {
+			return err
+		}
+
+		// Servers that do not support the offset return pages that repeat,
+		// overlap or rotate, so walking stops when a page has no new account
+		found := false
+		for _, account := range accounts {
+			if seen[account.ID] {
+				continue
+			}
+			seen[account.ID] = true
+			found = true
+
+			if err := fn(account); err != nil {
+				return err
+			}
+		}
+
+		if !found {
+			return nil
+		}
+
+		page.Offset += len(accounts)
+	}
+}
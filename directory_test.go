@@ -0,0 +1,210 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetDirectory(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case DirectoryURI:
+			q := r.URL.Query()
+			if q.Get("order") != DirectoryOrderNew || q.Get("local") != "true" || q.Get("limit") != "2" {
+				t.Errorf("invalid filters: %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, "[%s,%s]\n", testaccount, testaccount)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	accounts, err := client.GetDirectory(&DirectoryParams{Limit: 2, Order: DirectoryOrderNew, Local: true})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf("should have returned 2 accounts but instead returned: %d", len(accounts))
+	}
+}
+
+func TestWalkDirectory(t *testing.T) {
+	// Setup directory of 5 accounts
+	var account Account
+	err := json.Unmarshal([]byte(testaccount), &account)
+	if err != nil {
+		t.Fatalf("error unmarshalling test account: %v", err)
+	}
+	directory := Accounts{}
+	for i := 0; i < 5; i++ {
+		account.ID = strconv.Itoa(i)
+		directory = append(directory, account)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case DirectoryURI:
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			end := offset + limit
+			if offset > len(directory) {
+				offset = len(directory)
+			}
+			if end > len(directory) {
+				end = len(directory)
+			}
+
+			body, err := json.Marshal(directory[offset:end])
+			if err != nil {
+				t.Fatalf("error marshalling test accounts: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var ids []string
+	err = client.WalkDirectory(&DirectoryParams{Limit: 2}, func(a Account) error {
+		ids = append(ids, a.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(ids) != 5 || ids[4] != "4" {
+		t.Fatalf("should have walked 5 accounts but instead walked: %v", ids)
+	}
+
+	// Stop walking when the callback fails
+	stop := errors.New("stop")
+	count := 0
+	err = client.WalkDirectory(nil, func(a Account) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Fatalf("should have stopped after the first account: %v (%d)", err, count)
+	}
+}
+
+func TestWalkDirectoryIgnoredOffset(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case DirectoryURI:
+			atomic.AddInt32(&requests, 1)
+			fmt.Fprintf(w, "[%s]\n", testaccount)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The server returns the same account whatever the offset
+	count := 0
+	err = client.WalkDirectory(nil, func(a Account) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if count != 1 || atomic.LoadInt32(&requests) != 2 {
+		t.Fatalf("should have stopped at the repeated page: %d accounts, %d requests", count, atomic.LoadInt32(&requests))
+	}
+}
+
+func TestWalkDirectoryRotatingPages(t *testing.T) {
+	var account Account
+	err := json.Unmarshal([]byte(testaccount), &account)
+	if err != nil {
+		t.Fatalf("error unmarshalling test account: %v", err)
+	}
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case DirectoryURI:
+			// Ignore the offset and rotate through 3 accounts, 2 per page
+			n := int(atomic.AddInt32(&requests, 1))
+			page := Accounts{}
+			for i := 0; i < 2; i++ {
+				account.ID = strconv.Itoa((n + i) % 3)
+				page = append(page, account)
+			}
+
+			body, err := json.Marshal(page)
+			if err != nil {
+				t.Errorf("error marshalling test accounts: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ids := map[string]int{}
+	err = client.WalkDirectory(nil, func(a Account) error {
+		ids[a.ID]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(ids) != 3 || ids["0"] != 1 || ids["1"] != 1 || ids["2"] != 1 {
+		t.Fatalf("should have walked each of the 3 accounts once: %v", ids)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("should have stopped at the first page without new accounts: %d requests", n)
+	}
+}
@@ -0,0 +1,115 @@
+package mastodon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Character Mastodon uses to obfuscate the domains of blocks
+const obfuscationChar = '*'
+
+// Normalize a domain the way Mastodon stores it before hashing
+func normalizeBlockDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// DomainDigest returns the SHA-256 hex digest Mastodon publishes for a blocked
+// domain. Internationalized domains must be given in their punycode form
+func DomainDigest(domain string) string {
+	sum := sha256.Sum256([]byte(normalizeBlockDomain(domain)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Obfuscated reports whether the domain of the block is hidden with asterisks
+func (b DomainBlock) Obfuscated() bool {
+	return strings.ContainsRune(b.Domain, obfuscationChar)
+}
+
+// Matches reports whether the block is for exactly the candidate domain. The
+// digest is compared when the server sent one, otherwise the domain is
+func (b DomainBlock) Matches(domain string) bool {
+	if b.Digest != "" {
+		return strings.EqualFold(b.Digest, DomainDigest(domain))
+	}
+
+	return !b.Obfuscated() && normalizeBlockDomain(b.Domain) == normalizeBlockDomain(domain)
+}
+
+// Get the normalized domain followed by its parent domains
+func domainAndParents(domain string) []string {
+	domain = normalizeBlockDomain(domain)
+
+	var domains []string
+	for domain != "" {
+		domains = append(domains, domain)
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+
+	return domains
+}
+
+// Find the block that applies to the domain. Blocks also apply to the
+// subdomains of the blocked domain, so the parent domains are checked too
+func (d DomainsBlocked) Find(domain string) (DomainBlock, bool) {
+	for _, candidate := range domainAndParents(domain) {
+		for _, block := range d {
+			if block.Matches(candidate) {
+				return block, true
+			}
+		}
+	}
+
+	return DomainBlock{}, false
+}
+
+// Deobfuscate returns a copy of the blocks where each obfuscated domain is
+// replaced by the peer whose digest matches, for example the peers from
+// GetInstancePeers. The parent domains of the peers are checked too, since
+// peer lists often only hold subdomains of a blocked domain. Blocks without
+// a matching peer keep the obfuscated domain
+func (d DomainsBlocked) Deobfuscate(peers InstancePeers) DomainsBlocked {
+	blocks := append(DomainsBlocked(nil), d...)
+
+	digests := map[string]string{}
+	for _, block := range blocks {
+		if block.Obfuscated() && block.Digest != "" {
+			digests[strings.ToLower(block.Digest)] = ""
+		}
+	}
+	if len(digests) == 0 {
+		return blocks
+	}
+
+	hashed := map[string]bool{}
+	for _, peer := range peers {
+		for _, domain := range domainAndParents(peer) {
+			if hashed[domain] {
+				continue
+			}
+			hashed[domain] = true
+
+			digest := DomainDigest(domain)
+			if _, ok := digests[digest]; ok {
+				digests[digest] = domain
+			}
+		}
+	}
+
+	for i, block := range blocks {
+		if !block.Obfuscated() {
+			continue
+		}
+		if domain := digests[strings.ToLower(block.Digest)]; domain != "" {
+			blocks[i].Domain = domain
+		}
+	}
+
+	return blocks
+}
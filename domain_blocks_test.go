@@ -0,0 +1,85 @@
+package mastodon
+
+import (
+	"testing"
+)
+
+func TestDomainDigest(t *testing.T) {
+	// Digest of example.com as published by Mastodon
+	want := "a379a6f6eeafb9a55e378c118034e2751e682fab9f2d30ab13d2125586ce1947"
+
+	if got := DomainDigest("example.com"); got != want {
+		t.Fatalf("unexpected digest: %s", got)
+	}
+	if got := DomainDigest(" Example.COM. "); got != want {
+		t.Fatalf("domain should be normalized before hashing, got %s", got)
+	}
+}
+
+func TestDomainsBlockedFind(t *testing.T) {
+	blocks := DomainsBlocked{
+		{Domain: "ex*****.com", Digest: DomainDigest("example.com"), Severity: "suspend"},
+		{Domain: "spam.example", Severity: "silence"},
+	}
+
+	if !blocks[0].Obfuscated() || blocks[1].Obfuscated() {
+		t.Fatalf("unexpected obfuscation")
+	}
+
+	block, ok := blocks.Find("example.com")
+	if !ok || block.Severity != "suspend" {
+		t.Fatalf("example.com should match the obfuscated block")
+	}
+
+	block, ok = blocks.Find("media.Example.com")
+	if !ok || block.Severity != "suspend" {
+		t.Fatalf("subdomains should match the block of the parent domain")
+	}
+
+	block, ok = blocks.Find("spam.example")
+	if !ok || block.Severity != "silence" {
+		t.Fatalf("blocks without digest should match the domain")
+	}
+
+	if _, ok := blocks.Find("example.org"); ok {
+		t.Fatalf("example.org should not match")
+	}
+}
+
+func TestDomainsBlockedDeobfuscate(t *testing.T) {
+	blocks := DomainsBlocked{
+		{Domain: "ex*****.com", Digest: DomainDigest("example.com"), Severity: "suspend"},
+		{Domain: "un*****.social", Digest: DomainDigest("unknown.social"), Severity: "silence"},
+		{Domain: "plain.example", Digest: DomainDigest("plain.example"), Severity: "silence"},
+	}
+	peers := InstancePeers{"mastodon.social", "Example.com", "plain.example"}
+
+	deobfuscated := blocks.Deobfuscate(peers)
+
+	if deobfuscated[0].Domain != "example.com" {
+		t.Fatalf("example.com should be found among the peers, got %s", deobfuscated[0].Domain)
+	}
+	if deobfuscated[1].Domain != "un*****.social" {
+		t.Fatalf("blocks without matching peer should stay obfuscated, got %s", deobfuscated[1].Domain)
+	}
+	if deobfuscated[2].Domain != "plain.example" {
+		t.Fatalf("plain domains should not change, got %s", deobfuscated[2].Domain)
+	}
+	if blocks[0].Domain != "ex*****.com" {
+		t.Fatalf("the blocks should not be modified")
+	}
+}
+
+func TestDomainsBlockedDeobfuscateSubdomain(t *testing.T) {
+	blocks := DomainsBlocked{
+		{Domain: "e**l.example", Digest: DomainDigest("evil.example"), Severity: "suspend"},
+	}
+
+	// Only a subdomain of the blocked domain is known as a peer
+	peers := InstancePeers{"mastodon.social", "social.evil.example"}
+
+	deobfuscated := blocks.Deobfuscate(peers)
+	if deobfuscated[0].Domain != "evil.example" {
+		t.Fatalf("evil.example should be found from its subdomain, got %s", deobfuscated[0].Domain)
+	}
+}
@@ -0,0 +1,166 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Maximum number of bytes read from an error response body
+const maxErrorBodySize = 64 * 1024
+
+// Errors returned by instance endpoints that a server may restrict
+var (
+	// The server has turned the endpoint off, or does not implement it
+	ErrEndpointDisabled = errors.New("endpoint is disabled on this server")
+	// The server is in limited federation (whitelist) mode or disallows unauthenticated API access
+	ErrRequiresAuth = errors.New("endpoint requires an authenticated user")
+	// The server refused to expose the endpoint to the public
+	ErrNotPublic = errors.New("endpoint is not exposed publicly")
+)
+
+// Error returned for urls received from the server that point to another host
+var ErrOtherHost = errors.New("url points to another host")
+
+// Error returned before sending a request that the server version can not answer
+var ErrUnsupported = errors.New("unsupported on this server version")
+
+// APIError hold information for a non 200 response returned by the server
+type APIError struct {
+	StatusCode  int         `json:"-"`
+	Message     string      `json:"error"`
+	Description string      `json:"error_description,omitempty"`
+	URL         string      `json:"-"`
+	Header      http.Header `json:"-"`
+}
+
+// Error returns the status code, message and url of the failed request
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.Description != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Description)
+	}
+
+	return fmt.Sprintf("mastodon: %d %s (%s)", e.StatusCode, message, e.URL)
+}
+
+// Create an APIError from the response, parsing the Mastodon error body if present
+func newAPIError(url string, resp *http.Response) *APIError {
+	apierr := &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        url,
+		Header:     resp.Header,
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil {
+		// Servers do not always return JSON, ignore bodies that can not be parsed
+		_ = json.Unmarshal(body, apierr)
+	}
+
+	return apierr
+}
+
+// Check if the error is an APIError with the given status code
+func hasStatusCode(err error, code int) bool {
+	var apierr *APIError
+	if errors.As(err, &apierr) {
+		return apierr.StatusCode == code
+	}
+
+	return false
+}
+
+// IsNotFound reports whether the server responded with 404 Not Found
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether the server responded with 401 Unauthorized
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether the server responded with 403 Forbidden
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether the server responded with 429 Too Many Requests
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// EndpointError hold the reason a restricted endpoint could not be read.
+// Use errors.Is with ErrEndpointDisabled, ErrRequiresAuth or ErrNotPublic to
+// check the reason and errors.As to obtain the underlying APIError
+type EndpointError struct {
+	Endpoint string
+	Reason   error
+	Err      *APIError
+}
+
+// Error returns the reason and the underlying APIError
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Endpoint, e.Reason, e.Err)
+}
+
+// Unwrap returns the underlying APIError
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the reason of the error
+func (e *EndpointError) Is(target error) bool {
+	return target == e.Reason
+}
+
+// Classify why a restricted endpoint could not be read. Errors that do not
+// come from a 401, 403 or 404 response are returned unchanged
+func restrictedEndpointError(endpoint string, err error) error {
+	var apierr *APIError
+	if !errors.As(err, &apierr) {
+		return err
+	}
+
+	var reason error
+	switch apierr.StatusCode {
+	case http.StatusUnauthorized:
+		reason = ErrRequiresAuth
+	case http.StatusForbidden:
+		reason = ErrNotPublic
+	case http.StatusNotFound, http.StatusGone, http.StatusNotImplemented:
+		reason = ErrEndpointDisabled
+	default:
+		return err
+	}
+
+	return &EndpointError{
+		Endpoint: endpoint,
+		Reason:   reason,
+		Err:      apierr,
+	}
+}
+
+// UnsupportedError hold the feature that the known server version lacks.
+// Use errors.Is with ErrUnsupported to check for it
+type UnsupportedError struct {
+	Feature  Feature
+	Version  ServerVersion
+	Required ServerVersion
+}
+
+// Error returns the feature, the server version and the version that added the feature
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s: %s is %s, requires %s", e.Feature, e.Version.Raw, ErrUnsupported, e.Required)
+}
+
+// Is reports whether the target is ErrUnsupported
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
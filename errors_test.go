@@ -0,0 +1,89 @@
+package mastodon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case "/unauthorized":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintln(w, testunauthorized)
+			return
+		case "/ratelimited":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintln(w, `{"error": "Too many requests", "error_description": "Try again later"}`)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.SendRequest(ts.URL + "/unauthorized")
+	var apierr *APIError
+	if !errors.As(err, &apierr) {
+		t.Fatalf("should return an APIError instead got: %v", err)
+	}
+	if apierr.StatusCode != http.StatusUnauthorized || apierr.Message != "This API requires an authenticated user" {
+		t.Fatalf("error was incorrectly parsed: %+v", apierr)
+	}
+	if apierr.URL != ts.URL+"/unauthorized" {
+		t.Fatalf("url was incorrectly set to: %s", apierr.URL)
+	}
+	if !IsUnauthorized(err) || IsNotFound(err) {
+		t.Fatalf("should only be unauthorized: %v", err)
+	}
+
+	_, err = client.SendRequest(ts.URL + "/ratelimited")
+	if !IsRateLimited(err) {
+		t.Fatalf("should be rate limited: %v", err)
+	}
+	errors.As(err, &apierr)
+	if apierr.Description != "Try again later" || apierr.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("error was incorrectly parsed: %+v", apierr)
+	}
+
+	// Non JSON bodies should still return the status code
+	_, err = client.SendRequest(ts.URL + "/missing")
+	if !IsNotFound(err) {
+		t.Fatalf("should be not found: %v", err)
+	}
+	if IsNotFound(errors.New("not found")) {
+		t.Fatalf("plain errors should not be not found")
+	}
+}
+
+func TestRestrictedEndpointError(t *testing.T) {
+	forbidden := &APIError{StatusCode: http.StatusForbidden, URL: "https://mastodon.example" + InstanceDomainsBlockedyURI}
+	err := restrictedEndpointError(InstanceDomainsBlockedyURI, forbidden)
+	if !errors.Is(err, ErrNotPublic) {
+		t.Fatalf("should not be public instead got: %v", err)
+	}
+
+	var endpointerr *EndpointError
+	if !errors.As(err, &endpointerr) || endpointerr.Endpoint != InstanceDomainsBlockedyURI {
+		t.Fatalf("should return an EndpointError instead got: %v", err)
+	}
+
+	unavailable := &APIError{StatusCode: http.StatusServiceUnavailable}
+	err = restrictedEndpointError(InstancePeersURI, unavailable)
+	if err != error(unavailable) {
+		t.Fatalf("should return other errors unchanged instead got: %v", err)
+	}
+}
@@ -1,13 +1,14 @@
 package mastodon
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 )
 
 const (
 	InstanceURI                string = "/api/v2/instance"
+	InstanceV1URI              string = "/api/v1/instance"
 	InstanceActivityURI        string = "/api/v1/instance/activity"
 	InstanceDomainsBlockedyURI string = "/api/v1/instance/domain_block"
 	InstancePeersURI           string = "/api/v1/instance/peers"
@@ -55,12 +56,7 @@ type Instance struct {
 			VideoFrameRateLimit int      `json:"video_frame_rate_limit"`
 			VideoMatrixLimit    int      `json:"video_matrix_limit"`
 		} `json:"media_attachments"`
-		Polls struct {
-			MaxOptions             int `json:"max_options"`
-			MaxCharactersPerOption int `json:"max_characters_per_option"`
-			MinExpiration          int `json:"min_expiration"`
-			MaxExpiration          int `json:"max_expiration"`
-		} `json:"polls"`
+		Polls       PollsConfiguration `json:"polls"`
 		Translation struct {
 			Enabled bool `json:"enabled"`
 		} `json:"translation"`
@@ -71,42 +67,12 @@ type Instance struct {
 		Message          interface{} `json:"message"`
 	} `json:"registrations"`
 	Contact struct {
-		Email   string `json:"email"`
-		Account struct {
-			ID             string    `json:"id"`
-			Username       string    `json:"username"`
-			Acct           string    `json:"acct"`
-			DisplayName    string    `json:"display_name"`
-			Locked         bool      `json:"locked"`
-			Bot            bool      `json:"bot"`
-			Discoverable   bool      `json:"discoverable"`
-			Group          bool      `json:"group"`
-			CreatedAt      time.Time `json:"created_at"`
-			Note           string    `json:"note"`
-			URL            string    `json:"url"`
-			Avatar         string    `json:"avatar"`
-			AvatarStatic   string    `json:"avatar_static"`
-			Header         string    `json:"header"`
-			HeaderStatic   string    `json:"header_static"`
-			FollowersCount int       `json:"followers_count"`
-			FollowingCount int       `json:"following_count"`
-			StatusesCount  int       `json:"statuses_count"`
-			LastStatusAt   string    `json:"last_status_at"`
-			Noindex        bool      `json:"noindex"`
-			Emojis         []struct {
-				Shortcode       string `json:"shortcode"`
-				URL             string `json:"url"`
-				StaticURL       string `json:"static_url"`
-				VisibleInPicker bool   `json:"visible_in_picker"`
-			} `json:"emojis"`
-			Fields []struct {
-				Name       string      `json:"name"`
-				Value      string      `json:"value"`
-				VerifiedAt interface{} `json:"verified_at"`
-			} `json:"fields"`
-		} `json:"account"`
+		Email   string  `json:"email"`
+		Account Account `json:"account"`
 	} `json:"contact"`
 	Rules InstanceRules `json:"rules"`
+	// Only set when converted from /api/v1/instance, v2 has no statistics
+	Stats *InstanceStats `json:"stats,omitempty"`
 }
 
 // Rules hold rules for the instance
@@ -126,47 +92,88 @@ type InstanceActivity []struct {
 	Registrations string `json:"registrations"`
 }
 
-// DomainsBlocked hold information on domains blocked
-type DomainsBlocked []struct {
+// DomainBlock hold information for a domain blocked by the instance. The
+// domain may be obfuscated with asterisks, the digest is the SHA-256 of the
+// real domain
+type DomainBlock struct {
 	Domain   string `json:"domain"`
 	Digest   string `json:"digest"`
 	Severity string `json:"severity"`
 	Comment  string `json:"comment"`
 }
 
+// DomainsBlocked hold information on domains blocked
+type DomainsBlocked []DomainBlock
+
 // Error for unauthorized requests
+//
+// Deprecated: use APIError, which is returned for every non 200 response
 type Unauthorized struct {
 	Error string `json:"error"`
 }
 
-// Get general information about the server
+// Get general information about the server. Servers that do not serve
+// /api/v2/instance are asked for /api/v1/instance instead, which is converted
+// to the v2 model. The version that succeeded is remembered for the host,
+// and servers known to be older than Mastodon 4.0 are asked for v1 directly.
+// The version of the server is recorded for the capability checks
 func (c *Client) GetInstanceData() (Instance, error) {
+	return c.GetInstanceDataContext(context.Background())
+}
+
+// Same as GetInstanceData but the requests use the given context
+func (c *Client) GetInstanceDataContext(ctx context.Context) (Instance, error) {
 	instance := Instance{}
 
-	url := fmt.Sprintf("%s%s", c.Server, InstanceURI)
+	if c.InstanceVersion() != 1 && c.require(FeatureInstanceV2) == nil {
+		url := fmt.Sprintf("%s%s", c.Server, InstanceURI)
+
+		body, err := c.SendRequestContext(ctx, url)
+		if err == nil {
+			err = json.Unmarshal(body, &instance)
+			if err == nil {
+				c.setInstanceVersion(2)
+				c.setServerVersion(instance.Version, false)
+			}
+			return instance, err
+		}
+
+		if !isMissingEndpoint(err) {
+			return instance, err
+		}
+	}
 
-	body, err := c.SendRequest(url)
+	v1, err := c.GetInstanceDataV1Context(ctx)
 	if err != nil {
 		return instance, err
 	}
+	c.setInstanceVersion(1)
+	c.setServerVersion(v1.Version, false)
 
-	err = json.Unmarshal(body, &instance)
-
-	return instance, err
+	return v1.Instance(), nil
 }
 
-// Get domains that this instance is aware of
-// FIXME: Need to add additional check for if the server is in
-// whitelist mode and the Authorization header is missing or invalid
+// Get domains that this instance is aware of. Returns an EndpointError
+// with ErrRequiresAuth if the server is in whitelist mode, or with
+// ErrEndpointDisabled if the server does not publish its peers
 // https://docs.joinmastodon.org/methods/instance/#peers
 func (c *Client) GetInstancePeers() (InstancePeers, error) {
+	return c.GetInstancePeersContext(context.Background())
+}
+
+// Same as GetInstancePeers but the requests use the given context
+func (c *Client) GetInstancePeersContext(ctx context.Context) (InstancePeers, error) {
 	instancepeers := InstancePeers{}
 
+	if err := c.require(FeatureInstancePeers); err != nil {
+		return instancepeers, err
+	}
+
 	url := fmt.Sprintf("%s%s", c.Server, InstancePeersURI)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, url)
 	if err != nil {
-		return instancepeers, err
+		return instancepeers, restrictedEndpointError(InstancePeersURI, err)
 	}
 
 	err = json.Unmarshal(body, &instancepeers)
@@ -174,18 +181,27 @@ func (c *Client) GetInstancePeers() (InstancePeers, error) {
 	return instancepeers, err
 }
 
-// Get instance activity over the last 3 months, binned weekly
-// FIXME: Need to add additional check for if the server is in
-// whitelist mode and the Authorization header is missing or invalid
+// Get instance activity over the last 3 months, binned weekly. Returns an
+// EndpointError with ErrRequiresAuth if the server is in whitelist mode, or
+// with ErrEndpointDisabled if the server does not publish its activity
 // https://docs.joinmastodon.org/methods/instance/#activity
 func (c *Client) GetInstanceActivity() (InstanceActivity, error) {
+	return c.GetInstanceActivityContext(context.Background())
+}
+
+// Same as GetInstanceActivity but the requests use the given context
+func (c *Client) GetInstanceActivityContext(ctx context.Context) (InstanceActivity, error) {
 	instanceactivity := InstanceActivity{}
 
+	if err := c.require(FeatureInstanceActivity); err != nil {
+		return instanceactivity, err
+	}
+
 	url := fmt.Sprintf("%s%s", c.Server, InstanceActivityURI)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, url)
 	if err != nil {
-		return instanceactivity, err
+		return instanceactivity, restrictedEndpointError(InstanceActivityURI, err)
 	}
 
 	err = json.Unmarshal(body, &instanceactivity)
@@ -195,11 +211,20 @@ func (c *Client) GetInstanceActivity() (InstanceActivity, error) {
 
 // Get instance rules that the users of this service should follow
 func (c *Client) GetInstanceRules() (InstanceRules, error) {
+	return c.GetInstanceRulesContext(context.Background())
+}
+
+// Same as GetInstanceRules but the requests use the given context
+func (c *Client) GetInstanceRulesContext(ctx context.Context) (InstanceRules, error) {
 	instancerules := InstanceRules{}
 
+	if err := c.require(FeatureInstanceRules); err != nil {
+		return instancerules, err
+	}
+
 	url := fmt.Sprintf("%s%s", c.Server, InstanceRulesURI)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, url)
 	if err != nil {
 		return instancerules, err
 	}
@@ -209,18 +234,29 @@ func (c *Client) GetInstanceRules() (InstanceRules, error) {
 	return instancerules, err
 }
 
-// Get a list of domains that have been blocked
-// FIXME: Need to add additional check for if the server is in
-// whitelist mode and the Authorization header is missing or invalid
+// Get a list of domains that have been blocked. Returns an EndpointError
+// with ErrRequiresAuth if the server is in whitelist mode, with ErrNotPublic
+// if the server refuses to show the list, or with ErrEndpointDisabled if the
+// list is hidden. Mastodon answers 404 both when the list is disabled and when
+// it is only shown to logged in users, so these two cases can not be told apart
 // https://docs.joinmastodon.org/methods/instance/#domain_blocks
 func (c *Client) GetInstanceDomainsBlocked() (DomainsBlocked, error) {
+	return c.GetInstanceDomainsBlockedContext(context.Background())
+}
+
+// Same as GetInstanceDomainsBlocked but the requests use the given context
+func (c *Client) GetInstanceDomainsBlockedContext(ctx context.Context) (DomainsBlocked, error) {
 	domainsblocked := DomainsBlocked{}
 
+	if err := c.require(FeatureInstanceDomainBlocks); err != nil {
+		return domainsblocked, err
+	}
+
 	url := fmt.Sprintf("%s%s", c.Server, InstanceDomainsBlockedyURI)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, url)
 	if err != nil {
-		return domainsblocked, err
+		return domainsblocked, restrictedEndpointError(InstanceDomainsBlockedyURI, err)
 	}
 
 	err = json.Unmarshal(body, &domainsblocked)
@@ -2,6 +2,7 @@ package mastodon
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -348,30 +349,20 @@ func TestGetInstancePeers(t *testing.T) {
 	}
 }
 
-// FIXME - not currently implemented
-/*
 func TestGetInstancePeersWhitelisted(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Setup InstancePeers
 		instancepeers := InstancePeers{"tilde.zone", "mspsocial.net", "conf.tube"}
 
-		// Setup unauthorized error
-		var unauth Unauthorized
-		err := json.Unmarshal([]byte(testunauthorized), &unauth)
-		if err != nil {
-			t.Fatalf("error unmarshalling unauthorized error: %v", err)
-		}
-
 		// Return based on URI
 		switch r.URL.Path {
 		case InstancePeersURI:
 			auth := r.Header.Get("Authorization")
 			if auth == "" {
-				body, err := json.Marshal(unauth)
-				if err != nil {
-					t.Fatalf("error marshalling unauthorized error: %v", err)
-				}
-				http.Error(w, string(body), http.StatusUnauthorized)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintln(w, testunauthorized)
+				return
 			}
 
 			body, err := json.Marshal(instancepeers)
@@ -393,16 +384,35 @@ func TestGetInstancePeersWhitelisted(t *testing.T) {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	ip, err := client.GetInstancePeers()
+	_, err = client.GetInstancePeers()
+	if !errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should require auth instead got: %v", err)
+	}
+
+	var apierr *APIError
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("should wrap the unauthorized APIError: %v", err)
+	}
+}
+
+func TestGetInstancePeersDisabled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Endpoint disabled, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
 	if err != nil {
-		t.Fatalf("should not be fail: %v", err)
+		t.Fatalf("failed to create client: %v", err)
 	}
 
-	if len(ip) != 3 {
-		t.Fatalf("should have returned 3 peers but instead returned: %d", len(ip))
+	_, err = client.GetInstancePeers()
+	if !errors.Is(err, ErrEndpointDisabled) || errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should be disabled instead got: %v", err)
 	}
 }
-*/
 
 func TestGetInstanceActivity(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -445,8 +455,6 @@ func TestGetInstanceActivity(t *testing.T) {
 	}
 }
 
-// FIXME - not currently implemented
-/*
 func TestGetInstanceActivityWhitelisted(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Setup InstanceActivity
@@ -456,23 +464,15 @@ func TestGetInstanceActivityWhitelisted(t *testing.T) {
 			t.Fatalf("error unmarshalling test instance activity: %v", err)
 		}
 
-		// Setup unauthorized error
-		var unauth Unauthorized
-		err = json.Unmarshal([]byte(testunauthorized), &unauth)
-		if err != nil {
-			t.Fatalf("error unmarshalling unauthorized error: %v", err)
-		}
-
 		// Return based on URI
 		switch r.URL.Path {
 		case InstanceActivityURI:
 			auth := r.Header.Get("Authorization")
 			if auth == "" {
-				body, err := json.Marshal(unauth)
-				if err != nil {
-					t.Fatalf("error marshalling unauthorized error: %v", err)
-				}
-				http.Error(w, string(body), http.StatusUnauthorized)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintln(w, testunauthorized)
+				return
 			}
 
 			body, err := json.Marshal(instanceactivity)
@@ -494,16 +494,35 @@ func TestGetInstanceActivityWhitelisted(t *testing.T) {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	ia, err := client.GetInstanceActivity()
+	_, err = client.GetInstanceActivity()
+	if !errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should require auth instead got: %v", err)
+	}
+
+	var apierr *APIError
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("should wrap the unauthorized APIError: %v", err)
+	}
+}
+
+func TestGetInstanceActivityDisabled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Endpoint disabled, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
 	if err != nil {
-		t.Fatalf("should not be fail: %v", err)
+		t.Fatalf("failed to create client: %v", err)
 	}
 
-	if len(ia) != 12 {
-		t.Fatalf("should have returned 12 weeks but instead returned: %d", len(ia))
+	_, err = client.GetInstanceActivity()
+	if !errors.Is(err, ErrEndpointDisabled) || errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should be disabled instead got: %v", err)
 	}
 }
-*/
 
 func TestGetInstanceRules(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -587,8 +606,6 @@ func TestGetInstanceDomainsBlocked(t *testing.T) {
 	}
 }
 
-// FIXME - not currently implemented
-/*
 func TestGetInstanceDomainsBlockedWhitelisted(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Setup InstanceDomainsBlocked
@@ -598,23 +615,15 @@ func TestGetInstanceDomainsBlockedWhitelisted(t *testing.T) {
 			t.Fatalf("error unmarshalling test instance domains blocked: %v", err)
 		}
 
-		// Setup unauthorized error
-		var unauth Unauthorized
-		err = json.Unmarshal([]byte(testunauthorized), &unauth)
-		if err != nil {
-			t.Fatalf("error unmarshalling unauthorized error: %v", err)
-		}
-
 		// Return based on URI
 		switch r.URL.Path {
 		case InstanceDomainsBlockedyURI:
 			auth := r.Header.Get("Authorization")
 			if auth == "" {
-				body, err := json.Marshal(unauth)
-				if err != nil {
-					t.Fatalf("error marshalling unauthorized error: %v", err)
-				}
-				http.Error(w, string(body), http.StatusUnauthorized)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintln(w, testunauthorized)
+				return
 			}
 
 			body, err := json.Marshal(blockeddomains)
@@ -636,13 +645,32 @@ func TestGetInstanceDomainsBlockedWhitelisted(t *testing.T) {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	db, err := client.GetInstanceDomainsBlocked()
+	_, err = client.GetInstanceDomainsBlocked()
+	if !errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should require auth instead got: %v", err)
+	}
+
+	var apierr *APIError
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("should wrap the unauthorized APIError: %v", err)
+	}
+}
+
+func TestGetInstanceDomainsBlockedDisabled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Endpoint disabled, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
 	if err != nil {
-		t.Fatalf("should not be fail: %v", err)
+		t.Fatalf("failed to create client: %v", err)
 	}
 
-	if len(db) != 2 {
-		t.Fatalf("should have returned 2 blocked domains but instead returned: %d", len(db))
+	_, err = client.GetInstanceDomainsBlocked()
+	if !errors.Is(err, ErrEndpointDisabled) || errors.Is(err, ErrRequiresAuth) {
+		t.Fatalf("should be disabled instead got: %v", err)
 	}
 }
-*/
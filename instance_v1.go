@@ -0,0 +1,235 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// InstanceV1 hold information for instance as returned by /api/v1/instance,
+// which is the only version served by older Mastodon releases and by forks
+// such as Pleroma, Akkoma and GoToSocial
+type InstanceV1 struct {
+	URI              string `json:"uri"`
+	Title            string `json:"title"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+	Email            string `json:"email"`
+	Version          string `json:"version"`
+	Urls             struct {
+		StreamingAPI string `json:"streaming_api"`
+	} `json:"urls"`
+	Stats            InstanceStats `json:"stats"`
+	Thumbnail        string        `json:"thumbnail"`
+	Languages        []string      `json:"languages"`
+	Registrations    bool          `json:"registrations"`
+	ApprovalRequired bool          `json:"approval_required"`
+	InvitesEnabled   bool          `json:"invites_enabled"`
+	Configuration    struct {
+		Accounts struct {
+			MaxFeaturedTags int `json:"max_featured_tags"`
+		} `json:"accounts"`
+		Statuses struct {
+			MaxCharacters            int `json:"max_characters"`
+			MaxMediaAttachments      int `json:"max_media_attachments"`
+			CharactersReservedPerURL int `json:"characters_reserved_per_url"`
+		} `json:"statuses"`
+		MediaAttachments struct {
+			SupportedMimeTypes  []string `json:"supported_mime_types"`
+			ImageSizeLimit      int      `json:"image_size_limit"`
+			ImageMatrixLimit    int      `json:"image_matrix_limit"`
+			VideoSizeLimit      int      `json:"video_size_limit"`
+			VideoFrameRateLimit int      `json:"video_frame_rate_limit"`
+			VideoMatrixLimit    int      `json:"video_matrix_limit"`
+		} `json:"media_attachments"`
+		Polls PollsConfiguration `json:"polls"`
+	} `json:"configuration"`
+	ContactAccount *Account      `json:"contact_account"`
+	Rules          InstanceRules `json:"rules"`
+	// Limits sent by Pleroma and Akkoma instead of the configuration
+	MaxTootChars int `json:"max_toot_chars,omitempty"`
+	PollLimits   *struct {
+		MaxOptions     int `json:"max_options"`
+		MaxOptionChars int `json:"max_option_chars"`
+		MinExpiration  int `json:"min_expiration"`
+		MaxExpiration  int `json:"max_expiration"`
+	} `json:"poll_limits,omitempty"`
+}
+
+// InstanceStats hold the statistics of an instance, which are only returned
+// by /api/v1/instance
+type InstanceStats struct {
+	UserCount   int `json:"user_count"`
+	StatusCount int `json:"status_count"`
+	DomainCount int `json:"domain_count"`
+}
+
+// Instance converts the v1 instance into the v2 model. Fields that only exist
+// in v2, such as the monthly active users, are left empty, and the v1 stats
+// are kept in Stats
+func (v InstanceV1) Instance() Instance {
+	instance := Instance{}
+
+	instance.Domain = v.URI
+	if u, err := url.Parse(v.URI); err == nil && u.Host != "" {
+		instance.Domain = u.Host
+	}
+	instance.Title = v.Title
+	instance.Version = v.Version
+	instance.Description = v.ShortDescription
+	if instance.Description == "" {
+		instance.Description = v.Description
+	}
+	instance.Thumbnail.URL = v.Thumbnail
+	instance.Languages = v.Languages
+
+	config := &instance.Configuration
+	config.Urls.Streaming = v.Urls.StreamingAPI
+	config.Accounts.MaxFeaturedTags = v.Configuration.Accounts.MaxFeaturedTags
+	config.Statuses.MaxCharacters = v.Configuration.Statuses.MaxCharacters
+	config.Statuses.MaxMediaAttachments = v.Configuration.Statuses.MaxMediaAttachments
+	config.Statuses.CharactersReservedPerURL = v.Configuration.Statuses.CharactersReservedPerURL
+	config.MediaAttachments.SupportedMimeTypes = v.Configuration.MediaAttachments.SupportedMimeTypes
+	config.MediaAttachments.ImageSizeLimit = v.Configuration.MediaAttachments.ImageSizeLimit
+	config.MediaAttachments.ImageMatrixLimit = v.Configuration.MediaAttachments.ImageMatrixLimit
+	config.MediaAttachments.VideoSizeLimit = v.Configuration.MediaAttachments.VideoSizeLimit
+	config.MediaAttachments.VideoFrameRateLimit = v.Configuration.MediaAttachments.VideoFrameRateLimit
+	config.MediaAttachments.VideoMatrixLimit = v.Configuration.MediaAttachments.VideoMatrixLimit
+	config.Polls = v.Configuration.Polls
+
+	if config.Statuses.MaxCharacters == 0 {
+		config.Statuses.MaxCharacters = v.MaxTootChars
+	}
+	if config.Polls == (PollsConfiguration{}) && v.PollLimits != nil {
+		config.Polls = PollsConfiguration{
+			MaxOptions:             v.PollLimits.MaxOptions,
+			MaxCharactersPerOption: v.PollLimits.MaxOptionChars,
+			MinExpiration:          v.PollLimits.MinExpiration,
+			MaxExpiration:          v.PollLimits.MaxExpiration,
+		}
+	}
+
+	instance.Registrations.Enabled = v.Registrations
+	instance.Registrations.ApprovalRequired = v.ApprovalRequired
+	instance.Contact.Email = v.Email
+	if v.ContactAccount != nil {
+		instance.Contact.Account = *v.ContactAccount
+	}
+	instance.Rules = v.Rules
+
+	stats := v.Stats
+	instance.Stats = &stats
+
+	return instance
+}
+
+// InstanceVersions remembers which instance API version each host supports.
+// Share one between clients with WithInstanceVersions
+type InstanceVersions struct {
+	mu       sync.RWMutex
+	versions map[string]int
+}
+
+// NewInstanceVersions returns an empty set of instance API versions
+func NewInstanceVersions() *InstanceVersions {
+	return &InstanceVersions{
+		versions: map[string]int{},
+	}
+}
+
+// Get the instance API version that succeeded for the host, 0 when unknown
+func (v *InstanceVersions) Get(host string) int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.versions[strings.ToLower(host)]
+}
+
+// Set the instance API version that succeeded for the host
+func (v *InstanceVersions) Set(host string, version int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.versions[strings.ToLower(host)] = version
+}
+
+// Get the host of the server of the client
+func (c *Client) host() string {
+	u, err := url.Parse(c.Server)
+	if err != nil {
+		return c.Server
+	}
+
+	return u.Host
+}
+
+// InstanceVersion returns the instance API version, 1 or 2, that last
+// succeeded for the server, or 0 when GetInstanceData has not succeeded yet
+func (c *Client) InstanceVersion() int {
+	if c.versions == nil {
+		return 0
+	}
+
+	return c.versions.Get(c.host())
+}
+
+// Remember the instance API version that succeeded for the server
+func (c *Client) setInstanceVersion(version int) {
+	if c.versions != nil {
+		c.versions.Set(c.host(), version)
+	}
+}
+
+// Get general information about the server from the v1 endpoint
+func (c *Client) GetInstanceDataV1() (InstanceV1, error) {
+	return c.GetInstanceDataV1Context(context.Background())
+}
+
+// Same as GetInstanceDataV1 but the requests use the given context
+func (c *Client) GetInstanceDataV1Context(ctx context.Context) (InstanceV1, error) {
+	instance := InstanceV1{}
+
+	url := fmt.Sprintf("%s%s", c.Server, InstanceV1URI)
+
+	body, err := c.SendRequestContext(ctx, url)
+	if err != nil {
+		return instance, err
+	}
+
+	err = json.Unmarshal(body, &instance)
+
+	return instance, err
+}
+
+// Report whether the error means the server does not serve the endpoint
+func isMissingEndpoint(err error) bool {
+	var apierr *APIError
+	if !errors.As(err, &apierr) {
+		return false
+	}
+
+	switch apierr.StatusCode {
+	case http.StatusNotFound, http.StatusGone, http.StatusNotImplemented:
+		return true
+	}
+
+	return false
+}
+
+// WithInstanceVersions shares the record of instance API versions between
+// clients, for example all the clients of a Pool
+func WithInstanceVersions(versions *InstanceVersions) Option {
+	return func(c *Client) error {
+		if versions == nil {
+			return errors.New("instance versions can not be nil")
+		}
+		c.versions = versions
+
+		return nil
+	}
+}
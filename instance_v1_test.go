@@ -0,0 +1,171 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	testinstancev1 string = `{
+		"uri": "pleroma.example",
+		"title": "Pleroma",
+		"short_description": "A small Pleroma server",
+		"description": "A small Pleroma server for testing",
+		"email": "admin@pleroma.example",
+		"version": "2.7.2 (compatible; Pleroma 2.5.0)",
+		"urls": {
+		  "streaming_api": "wss://pleroma.example"
+		},
+		"stats": {
+		  "user_count": 12,
+		  "status_count": 3400,
+		  "domain_count": 870
+		},
+		"thumbnail": "https://pleroma.example/instance/thumbnail.jpeg",
+		"languages": [
+		  "en"
+		],
+		"registrations": true,
+		"approval_required": true,
+		"invites_enabled": false,
+		"max_toot_chars": 5000,
+		"poll_limits": {
+		  "max_options": 20,
+		  "max_option_chars": 200,
+		  "min_expiration": 0,
+		  "max_expiration": 31536000
+		},
+		"contact_account": null,
+		"rules": []
+	}`
+)
+
+func TestGetInstanceDataV1Fallback(t *testing.T) {
+	var v2requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case InstanceV1URI:
+			fmt.Fprintln(w, testinstancev1)
+			return
+		case InstanceURI:
+			atomic.AddInt32(&v2requests, 1)
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if client.InstanceVersion() != 0 {
+		t.Fatalf("version should be unknown before the first request, got %d", client.InstanceVersion())
+	}
+
+	for i := 0; i < 2; i++ {
+		instance, err := client.GetInstanceData()
+		if err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+
+		if instance.Domain != "pleroma.example" || instance.Title != "Pleroma" {
+			t.Fatalf("unexpected instance: %s %s", instance.Domain, instance.Title)
+		}
+		if instance.Description != "A small Pleroma server" {
+			t.Fatalf("unexpected description: %s", instance.Description)
+		}
+		if instance.Configuration.Urls.Streaming != "wss://pleroma.example" {
+			t.Fatalf("unexpected streaming url: %s", instance.Configuration.Urls.Streaming)
+		}
+		if instance.Configuration.Statuses.MaxCharacters != 5000 {
+			t.Fatalf("max characters should come from max_toot_chars, got %d", instance.Configuration.Statuses.MaxCharacters)
+		}
+		if instance.Configuration.Polls.MaxOptions != 20 || instance.Configuration.Polls.MaxCharactersPerOption != 200 {
+			t.Fatalf("polls should come from poll_limits, got %+v", instance.Configuration.Polls)
+		}
+		if !instance.Registrations.Enabled || !instance.Registrations.ApprovalRequired {
+			t.Fatalf("unexpected registrations: %+v", instance.Registrations)
+		}
+		if instance.Contact.Email != "admin@pleroma.example" {
+			t.Fatalf("unexpected contact email: %s", instance.Contact.Email)
+		}
+		if instance.Stats == nil || *instance.Stats != (InstanceStats{UserCount: 12, StatusCount: 3400, DomainCount: 870}) {
+			t.Fatalf("stats should be kept from v1, got %+v", instance.Stats)
+		}
+	}
+
+	if client.InstanceVersion() != 1 {
+		t.Fatalf("version 1 should be remembered, got %d", client.InstanceVersion())
+	}
+
+	// The second request should go directly to v1
+	if n := atomic.LoadInt32(&v2requests); n != 1 {
+		t.Fatalf("v2 should be requested once, got %d", n)
+	}
+}
+
+func TestGetInstanceDataV2Remembered(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == InstanceURI {
+			fmt.Fprintln(w, testinstance)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	versions := NewInstanceVersions()
+	client, err := NewClient(ts.URL, WithInstanceVersions(versions))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.GetInstanceData(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// Clients sharing the versions see the result of each other
+	other, err := NewClient(ts.URL, WithInstanceVersions(versions))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if other.InstanceVersion() != 2 {
+		t.Fatalf("version 2 should be shared, got %d", other.InstanceVersion())
+	}
+
+	if _, err := NewClient(ts.URL, WithInstanceVersions(nil)); err == nil {
+		t.Fatalf("nil versions should fail")
+	}
+}
+
+func TestGetInstanceDataNoFallback(t *testing.T) {
+	var v1requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == InstanceV1URI {
+			atomic.AddInt32(&v1requests, 1)
+		}
+
+		http.Error(w, testunauthorized, http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// Only a missing endpoint should cause a fallback to v1
+	_, err = client.GetInstanceData()
+	if !IsUnauthorized(err) {
+		t.Fatalf("should be unauthorized: %v", err)
+	}
+	if n := atomic.LoadInt32(&v1requests); n != 0 {
+		t.Fatalf("v1 should not be requested, got %d", n)
+	}
+}
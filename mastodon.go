@@ -1,10 +1,12 @@
 package mastodon
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -21,61 +23,293 @@ type Client struct {
 	http.Client
 	Server    string
 	UserAgent string
+
+	headers   http.Header
+	ratelimit *rateLimiter
+	pacer     *pacer
+	retry     *RetryPolicy
+	cache     Cache
+	streaming string
+	origin    string
+	versions  *InstanceVersions
+	version   *versionTracker
 }
 
-// NewClient returns a new mastodon API client.
-func NewClient(server string) (*Client, error) {
+// NewClient returns a new mastodon API client. The options are applied in
+// order after the defaults have been set.
+func NewClient(server string, opts ...Option) (*Client, error) {
 	// Check that the user provided a valid schema
-	serverlower := strings.ToLower(server)
-	if !strings.HasPrefix(serverlower, "https://") && !strings.HasPrefix(serverlower, "http://") {
-		e := fmt.Sprintf("invalid server provided: %s", server)
-		return &Client{}, errors.New(e)
+	if err := validateServer(server); err != nil {
+		return &Client{}, err
 	}
 
 	c := &Client{
 		Client:    *http.DefaultClient,
 		Server:    server,
 		UserAgent: UserAgent,
+		headers:   http.Header{},
+		ratelimit: &rateLimiter{},
+		origin:    server,
+		versions:  NewInstanceVersions(),
+		version:   &versionTracker{},
 	}
 
 	// Set default timeout
 	c.Client.Timeout = time.Second * Timeout
 
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return &Client{}, err
+		}
+	}
+
 	return c, nil
 }
 
+// Check that the server has a valid schema
+func validateServer(server string) error {
+	serverlower := strings.ToLower(server)
+	if !strings.HasPrefix(serverlower, "https://") && !strings.HasPrefix(serverlower, "http://") {
+		e := fmt.Sprintf("invalid server provided: %s", server)
+		return errors.New(e)
+	}
+
+	return nil
+}
+
+// PageParams hold the ID based paging parameters shared by list endpoints
+type PageParams struct {
+	MaxID   string
+	SinceID string
+	MinID   string
+	Limit   int
+}
+
+// Add the paging parameters to the query values
+func (p PageParams) encode(v url.Values) {
+	if p.MaxID != "" {
+		v.Set("max_id", p.MaxID)
+	}
+	if p.SinceID != "" {
+		v.Set("since_id", p.SinceID)
+	}
+	if p.MinID != "" {
+		v.Set("min_id", p.MinID)
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+}
+
+// Build the full url for the URI and query values
+func (c *Client) buildURL(uri string, v url.Values) string {
+	endpoint := fmt.Sprintf("%s%s", c.Server, uri)
+	if len(v) > 0 {
+		endpoint += "?" + v.Encode()
+	}
+
+	return endpoint
+}
+
+// Rebuild a url received from the server, such as a Link header, on the
+// server of the client so that the base url is kept. Urls to hosts other than
+// the server and the base url are rejected, so custom headers are never sent
+// to them
+func (c *Client) serverURL(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	base, err := url.Parse(c.Server)
+	if err != nil {
+		return "", err
+	}
+
+	if u.IsAbs() || u.Host != "" {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", fmt.Errorf("%w: %s", ErrOtherHost, link)
+		}
+
+		origin, _ := url.Parse(c.origin)
+		switch {
+		case strings.EqualFold(u.Host, base.Host):
+			// Urls built on the base url already hold its path
+			u.Path = strings.TrimPrefix(u.Path, strings.TrimSuffix(base.Path, "/"))
+		case origin != nil && strings.EqualFold(u.Host, origin.Host):
+		default:
+			return "", fmt.Errorf("%w: %s", ErrOtherHost, link)
+		}
+	}
+
+	endpoint := c.Server + u.EscapedPath()
+	if u.RawQuery != "" {
+		endpoint += "?" + u.RawQuery
+	}
+
+	return endpoint, nil
+}
+
 // Send request and obtain body
 func (c *Client) SendRequest(url string) ([]byte, error) {
+	return c.SendRequestContext(context.Background(), url)
+}
+
+// Send request with the given context and obtain body. The request is
+// aborted when the context is cancelled or its deadline is exceeded
+func (c *Client) SendRequestContext(ctx context.Context, url string) ([]byte, error) {
+	body, _, err := c.sendRequest(ctx, url)
+
+	return body, err
+}
+
+// Send request and obtain body and response headers, waiting for the rate
+// limit and retrying according to the retry policy of the client
+func (c *Client) sendRequest(ctx context.Context, url string) ([]byte, http.Header, error) {
+	if c.cache != nil {
+		if entry, ok := c.cache.Get(url); ok && entry.Fresh(time.Now()) {
+			return entry.Body, entry.Header, nil
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		body, header, err := c.sendRateLimited(ctx, url)
+		if err == nil {
+			return body, header, nil
+		}
+
+		statusCode := 0
+		var apierr *APIError
+		if errors.As(err, &apierr) {
+			statusCode = apierr.StatusCode
+		}
+
+		// Rate limited requests are handled by the rate limiter when it waits
+		if statusCode == http.StatusTooManyRequests && c.ratelimit.waiting() {
+			return body, header, err
+		}
+
+		if !c.retry.retry(attempt, statusCode, err) {
+			return body, header, err
+		}
+
+		delay, ok := c.retry.delay(attempt, header)
+		if !ok {
+			return body, header, err
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return body, header, err
+		}
+	}
+}
+
+// Send request and obtain body and response headers, waiting for the rate limit
+func (c *Client) sendRateLimited(ctx context.Context, url string) ([]byte, http.Header, error) {
+	if c.ratelimit.waiting() {
+		if err := c.ratelimit.waitReset(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := c.pacer.wait(ctx, c.host()); err != nil {
+		return nil, nil, err
+	}
+
+	body, header, err := c.do(ctx, url)
+
+	// Retry once after the rate limit resets, unless the reset is too far away
+	if IsRateLimited(err) && c.ratelimit.waiting() {
+		d, ok := c.ratelimit.untilReset(time.Now())
+		if !ok {
+			return body, header, err
+		}
+		if err := sleepContext(ctx, d); err != nil {
+			return nil, header, err
+		}
+		if err := c.pacer.wait(ctx, c.host()); err != nil {
+			return nil, header, err
+		}
+		body, header, err = c.do(ctx, url)
+	}
+
+	return body, header, err
+}
+
+// Send a single request and obtain body and response headers. Stale cache
+// entries are revalidated and 200 responses are stored in the cache
+func (c *Client) do(ctx context.Context, url string) ([]byte, http.Header, error) {
 	var data []byte
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return data, err
+		return data, nil, err
 	}
 
 	req.Header.Set("User-Agent", c.UserAgent)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	// Ask the server if the cached body is still valid
+	var entry *CacheEntry
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(url); ok {
+			entry = cached
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
 
 	// Send request
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		return data, err
+		return data, nil, err
 	}
 	defer resp.Body.Close()
 
+	c.ratelimit.update(resp.Header)
+
+	// Cached body is still valid, extend its lifetime. The entry may be
+	// shared with concurrent requests, so a copy is updated
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		maxage, store := parseCacheControl(resp.Header.Get("Cache-Control"))
+		updated := *entry
+		updated.Expires = time.Time{}
+		if maxage > 0 {
+			updated.Expires = time.Now().Add(maxage)
+		}
+		if store {
+			c.cache.Set(url, &updated)
+		}
+
+		return updated.Body, updated.Header, nil
+	}
+
 	// Verify response was 200
 	if resp.StatusCode != 200 {
-		err = errors.New(
-			"resp.StatusCode: " +
-				strconv.Itoa(resp.StatusCode))
-		return data, err
+		return data, resp.Header, newAPIError(url, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return data, err
+		return data, resp.Header, err
 	}
 
 	data = body
 
-	return data, nil
+	// Cache failures do not fail the request
+	if c.cache != nil {
+		if fresh, ok := newCacheEntry(data, resp.Header, time.Now()); ok {
+			c.cache.Set(url, fresh)
+		} else if entry != nil {
+			c.cache.Delete(url)
+		}
+	}
+
+	return data, resp.Header, nil
 }
@@ -1,7 +1,9 @@
 package mastodon
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -77,3 +79,39 @@ func TestSendRequest(t *testing.T) {
 	}
 
 }
+
+func TestSendRequestContext(t *testing.T) {
+	// Setup http server that responds slower than the deadline
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	// Create client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// Deadline exceeded
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.SendRequestContext(ctx, ts.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("should exceed the deadline instead got: %v", err)
+	}
+
+	// Cancelled before the request
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.GetInstancePeersContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("should be cancelled instead got: %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	NodeInfoWellKnownURI string = "/.well-known/nodeinfo"
+	HostMetaURI          string = "/.well-known/host-meta"
+
+	// Schemas of the NodeInfo documents linked from the well-known document
+	NodeInfoSchema20 string = "http://nodeinfo.diaspora.software/ns/schema/2.0"
+	NodeInfoSchema21 string = "http://nodeinfo.diaspora.software/ns/schema/2.1"
+)
+
+// Error returned when the server does not link a NodeInfo 2.0 or 2.1 document
+var ErrNoNodeInfo = errors.New("no supported nodeinfo document")
+
+// Software names, as reported by NodeInfo, of servers that implement the
+// Mastodon client API. Forks such as glitch-soc and Hometown report mastodon
+var MastodonCompatibleSoftware = []string{
+	"mastodon",
+	"pleroma",
+	"akkoma",
+	"gotosocial",
+	"takahe",
+	"iceshrimp",
+	"sharkey",
+	"friendica",
+}
+
+// Link hold a link of a well-known document such as host-meta or nodeinfo
+type Link struct {
+	Rel      string `json:"rel" xml:"rel,attr"`
+	Type     string `json:"type,omitempty" xml:"type,attr,omitempty"`
+	Href     string `json:"href,omitempty" xml:"href,attr,omitempty"`
+	Template string `json:"template,omitempty" xml:"template,attr,omitempty"`
+}
+
+// NodeInfoLinks hold the links to the NodeInfo documents of the server
+type NodeInfoLinks struct {
+	Links []Link `json:"links"`
+}
+
+// HostMeta hold the links of the host-meta XRD document of the server
+type HostMeta struct {
+	Subject string `xml:"Subject,omitempty"`
+	Links   []Link `xml:"Link"`
+}
+
+// Get the link with the given rel
+func (h HostMeta) Link(rel string) (Link, bool) {
+	for _, link := range h.Links {
+		if link.Rel == rel {
+			return link, true
+		}
+	}
+
+	return Link{}, false
+}
+
+// NodeInfo hold information for the software, protocols and usage of a server
+type NodeInfo struct {
+	Version  string `json:"version"`
+	Software struct {
+		Name       string `json:"name"`
+		Version    string `json:"version"`
+		Repository string `json:"repository,omitempty"`
+		Homepage   string `json:"homepage,omitempty"`
+	} `json:"software"`
+	Protocols []string `json:"protocols"`
+	Services  struct {
+		Inbound  []string `json:"inbound"`
+		Outbound []string `json:"outbound"`
+	} `json:"services"`
+	Usage struct {
+		Users struct {
+			Total          int `json:"total"`
+			ActiveMonth    int `json:"activeMonth"`
+			ActiveHalfyear int `json:"activeHalfyear"`
+		} `json:"users"`
+		LocalPosts    int `json:"localPosts"`
+		LocalComments int `json:"localComments,omitempty"`
+	} `json:"usage"`
+	OpenRegistrations bool                   `json:"openRegistrations"`
+	Metadata          map[string]interface{} `json:"metadata"`
+}
+
+// MastodonCompatible reports whether the software is in MastodonCompatibleSoftware
+func (n NodeInfo) MastodonCompatible() bool {
+	name := strings.ToLower(n.Software.Name)
+	for _, software := range MastodonCompatibleSoftware {
+		if name == software {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Mastodon reports whether the server runs Mastodon or one of its forks
+func (n NodeInfo) Mastodon() bool {
+	return strings.EqualFold(n.Software.Name, "mastodon")
+}
+
+// Get the links to the NodeInfo documents of the server
+func (c *Client) GetNodeInfoLinks() (NodeInfoLinks, error) {
+	return c.GetNodeInfoLinksContext(context.Background())
+}
+
+// Same as GetNodeInfoLinks but the requests use the given context
+func (c *Client) GetNodeInfoLinksContext(ctx context.Context) (NodeInfoLinks, error) {
+	links := NodeInfoLinks{}
+
+	url := fmt.Sprintf("%s%s", c.Server, NodeInfoWellKnownURI)
+
+	body, err := c.SendRequestContext(ctx, url)
+	if err != nil {
+		return links, err
+	}
+
+	err = json.Unmarshal(body, &links)
+
+	return links, err
+}
+
+// Get the NodeInfo document of the server, preferring schema 2.1 over 2.0.
+// Returns ErrNoNodeInfo if the server links neither, and ErrOtherHost if the
+// link points to another host
+func (c *Client) GetNodeInfo() (NodeInfo, error) {
+	return c.GetNodeInfoContext(context.Background())
+}
+
+// Same as GetNodeInfo but the requests use the given context
+func (c *Client) GetNodeInfoContext(ctx context.Context) (NodeInfo, error) {
+	nodeinfo := NodeInfo{}
+
+	links, err := c.GetNodeInfoLinksContext(ctx)
+	if err != nil {
+		return nodeinfo, err
+	}
+
+	href := ""
+	for _, schema := range []string{NodeInfoSchema21, NodeInfoSchema20} {
+		for _, link := range links.Links {
+			if link.Rel == schema && link.Href != "" {
+				href = link.Href
+				break
+			}
+		}
+		if href != "" {
+			break
+		}
+	}
+	if href == "" {
+		return nodeinfo, ErrNoNodeInfo
+	}
+
+	// Never follow the link off the server
+	endpoint, err := c.serverURL(href)
+	if err != nil {
+		return nodeinfo, err
+	}
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return nodeinfo, err
+	}
+
+	err = json.Unmarshal(body, &nodeinfo)
+
+	return nodeinfo, err
+}
+
+// Get the host-meta document of the server, which links to the webfinger endpoint
+func (c *Client) GetHostMeta() (HostMeta, error) {
+	return c.GetHostMetaContext(context.Background())
+}
+
+// Same as GetHostMeta but the requests use the given context
+func (c *Client) GetHostMetaContext(ctx context.Context) (HostMeta, error) {
+	hostmeta := HostMeta{}
+
+	url := fmt.Sprintf("%s%s", c.Server, HostMetaURI)
+
+	body, err := c.SendRequestContext(ctx, url)
+	if err != nil {
+		return hostmeta, err
+	}
+
+	err = xml.Unmarshal(body, &hostmeta)
+
+	return hostmeta, err
+}
+
+// Check whether the server implements the Mastodon client API using its
+// NodeInfo document, before calling GetInstanceData. The version of servers
+// running Mastodon is recorded for the capability checks
+func (c *Client) IsMastodonCompatible() (bool, NodeInfo, error) {
+	return c.IsMastodonCompatibleContext(context.Background())
+}
+
+// Same as IsMastodonCompatible but the requests use the given context
+func (c *Client) IsMastodonCompatibleContext(ctx context.Context) (bool, NodeInfo, error) {
+	nodeinfo, err := c.GetNodeInfoContext(ctx)
+	if err != nil {
+		return false, nodeinfo, err
+	}
+
+	if nodeinfo.Mastodon() {
+		c.setServerVersion(nodeinfo.Software.Version, true)
+	}
+
+	return nodeinfo.MastodonCompatible(), nodeinfo, nil
+}
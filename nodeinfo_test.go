@@ -0,0 +1,185 @@
+package mastodon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	testnodeinfo string = `{
+		"version": "2.0",
+		"software": {
+		  "name": "mastodon",
+		  "version": "4.0.2+glitch"
+		},
+		"protocols": [
+		  "activitypub"
+		],
+		"services": {
+		  "outbound": [],
+		  "inbound": []
+		},
+		"usage": {
+		  "users": {
+			"total": 2040,
+			"activeMonth": 812,
+			"activeHalfyear": 1303
+		  },
+		  "localPosts": 198233
+		},
+		"openRegistrations": true,
+		"metadata": {}
+	}`
+	testhostmeta string = `<?xml version="1.0" encoding="UTF-8"?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0">
+  <Link rel="lrdd" template="https://mastodon.example/.well-known/webfinger?resource={uri}"/>
+</XRD>`
+)
+
+func TestGetNodeInfo(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case NodeInfoWellKnownURI:
+			fmt.Fprintf(w, `{"links": [{"rel": "%s", "href": "%s/nodeinfo/2.0"}]}`, NodeInfoSchema20, ts.URL)
+			return
+		case "/nodeinfo/2.0":
+			fmt.Fprintln(w, testnodeinfo)
+			return
+		case HostMetaURI:
+			fmt.Fprintln(w, testhostmeta)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	compatible, nodeinfo, err := client.IsMastodonCompatible()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if !compatible || !nodeinfo.Mastodon() {
+		t.Fatalf("mastodon should be compatible")
+	}
+	if nodeinfo.Usage.Users.ActiveMonth != 812 || !nodeinfo.OpenRegistrations {
+		t.Fatalf("unexpected nodeinfo: %+v", nodeinfo)
+	}
+	if len(nodeinfo.Protocols) != 1 || nodeinfo.Protocols[0] != "activitypub" {
+		t.Fatalf("unexpected protocols: %v", nodeinfo.Protocols)
+	}
+
+	v, ok := client.ServerVersion()
+	if !ok || v.Fork != "glitch" {
+		t.Fatalf("server version should be recorded, got %+v", v)
+	}
+
+	hostmeta, err := client.GetHostMeta()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	link, ok := hostmeta.Link("lrdd")
+	if !ok || link.Template != "https://mastodon.example/.well-known/webfinger?resource={uri}" {
+		t.Fatalf("unexpected lrdd link: %+v", link)
+	}
+}
+
+func TestGetNodeInfoPrefers21(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case NodeInfoWellKnownURI:
+			fmt.Fprintf(w, `{"links": [{"rel": "%s", "href": "%s/nodeinfo/2.0"}, {"rel": "%s", "href": "%s/nodeinfo/2.1"}]}`,
+				NodeInfoSchema20, ts.URL, NodeInfoSchema21, ts.URL)
+			return
+		case "/nodeinfo/2.1":
+			fmt.Fprintln(w, `{"version": "2.1", "software": {"name": "misskey", "version": "13.0.0"}, "protocols": ["activitypub"]}`)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	compatible, nodeinfo, err := client.IsMastodonCompatible()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if nodeinfo.Version != "2.1" {
+		t.Fatalf("schema 2.1 should be preferred, got %s", nodeinfo.Version)
+	}
+	if compatible {
+		t.Fatalf("misskey should not be compatible")
+	}
+	if _, ok := client.ServerVersion(); ok {
+		t.Fatalf("version of other software should not be recorded")
+	}
+}
+
+func TestGetNodeInfoMissing(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == NodeInfoWellKnownURI {
+			fmt.Fprintln(w, `{"links": [{"rel": "http://nodeinfo.diaspora.software/ns/schema/1.0", "href": "/nodeinfo/1.0"}]}`)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.GetNodeInfo()
+	if !errors.Is(err, ErrNoNodeInfo) {
+		t.Fatalf("should be ErrNoNodeInfo: %v", err)
+	}
+}
+
+func TestGetNodeInfoOtherHost(t *testing.T) {
+	var requests int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprintln(w, testnodeinfo)
+	}))
+	defer other.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == NodeInfoWellKnownURI {
+			fmt.Fprintf(w, `{"links": [{"rel": "%s", "href": "%s/nodeinfo/2.0"}]}`, NodeInfoSchema20, other.URL)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.GetNodeInfo()
+	if !errors.Is(err, ErrOtherHost) {
+		t.Fatalf("should be ErrOtherHost: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("the other host should not be requested, got %d requests", n)
+	}
+}
@@ -0,0 +1,88 @@
+package mastodon
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created by NewClient
+type Option func(*Client) error
+
+// WithHTTPClient uses a copy of the given http.Client, including its timeout
+// and transport, to send requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return errors.New("http client can not be nil")
+		}
+		c.Client = *hc
+
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit for each request. A timeout of zero means no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return errors.New("timeout can not be negative")
+		}
+		c.Client.Timeout = timeout
+
+		return nil
+	}
+}
+
+// WithTransport sets the transport used to send requests, such as an
+// instrumented or proxied http.RoundTripper
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		if transport == nil {
+			return errors.New("transport can not be nil")
+		}
+		c.Client.Transport = transport
+
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request
+func WithUserAgent(useragent string) Option {
+	return func(c *Client) error {
+		if useragent == "" {
+			return errors.New("user-agent can not be empty")
+		}
+		c.UserAgent = useragent
+
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with each request. Headers set this way
+// take precedence over the User-Agent of the client
+func WithHeader(key string, value string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return errors.New("header key can not be empty")
+		}
+		c.headers.Add(key, value)
+
+		return nil
+	}
+}
+
+// WithBaseURL sends requests to the given URL instead of the server, for
+// example to reach the API through a proxy or under a path prefix such as
+// https://proxy.example/mastodon
+func WithBaseURL(baseurl string) Option {
+	return func(c *Client) error {
+		if err := validateServer(baseurl); err != nil {
+			return err
+		}
+		c.Server = strings.TrimSuffix(baseurl, "/")
+
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Transport that counts the requests sent through it
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case "/mastodon" + InstancePeersURI:
+			if r.Header.Get("User-Agent") != "crawler/1.0" || r.Header.Get("X-Tenant") != "blue" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `["tilde.zone"]`)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient("https://mastodon.example",
+		WithTimeout(time.Second),
+		WithTransport(transport),
+		WithUserAgent("crawler/1.0"),
+		WithHeader("X-Tenant", "blue"),
+		WithBaseURL(ts.URL+"/mastodon/"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if client.Timeout != time.Second {
+		t.Fatalf("timeout was incorrectly set to: %s", client.Timeout)
+	}
+
+	if client.Server != ts.URL+"/mastodon" {
+		t.Fatalf("server was incorrectly set to: %s", client.Server)
+	}
+
+	peers, err := client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(peers) != 1 || transport.count != 1 {
+		t.Fatalf("should have sent 1 request through the transport: %d peers, %d requests", len(peers), transport.count)
+	}
+}
+
+func TestNewClientInvalidOptions(t *testing.T) {
+	options := map[string]Option{
+		"nil http client":  WithHTTPClient(nil),
+		"negative timeout": WithTimeout(-time.Second),
+		"nil transport":    WithTransport(nil),
+		"empty user-agent": WithUserAgent(""),
+		"empty header key": WithHeader("", "value"),
+		"invalid base url": WithBaseURL("mastodon.example"),
+	}
+
+	for name, opt := range options {
+		_, err := NewClient("https://mastodon.example", opt)
+		if err == nil {
+			t.Fatalf("should fail for %s", name)
+		}
+	}
+
+	hc := &http.Client{Timeout: 3 * time.Second}
+	client, err := NewClient("https://mastodon.example", WithHTTPClient(hc))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if client.Timeout != 3*time.Second {
+		t.Fatalf("timeout was incorrectly set to: %s", client.Timeout)
+	}
+}
@@ -0,0 +1,238 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Pagination hold the paging information returned in the Link header
+type Pagination struct {
+	NextURL string
+	PrevURL string
+	MaxID   string
+	MinID   string
+	SinceID string
+}
+
+// Next returns the paging parameters for the next (older) page
+func (p Pagination) Next() PageParams {
+	return PageParams{MaxID: p.MaxID}
+}
+
+// Prev returns the paging parameters for the previous (newer) page
+func (p Pagination) Prev() PageParams {
+	return PageParams{MinID: p.MinID, SinceID: p.SinceID}
+}
+
+// HasNext reports whether the server returned a link to an older page
+func (p Pagination) HasNext() bool {
+	return p.NextURL != ""
+}
+
+// HasPrev reports whether the server returned a link to a newer page
+func (p Pagination) HasPrev() bool {
+	return p.PrevURL != ""
+}
+
+// Parse a Link header such as <https://...?max_id=1>; rel="next", <https://...?min_id=2>; rel="prev"
+func parseLinkHeader(header string) Pagination {
+	pagination := Pagination{}
+
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = strings.Trim(target, "<>")
+
+		u, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		q := u.Query()
+
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "rel=") {
+				continue
+			}
+
+			switch strings.Trim(strings.TrimPrefix(param, "rel="), `"`) {
+			case "next":
+				pagination.NextURL = target
+				pagination.MaxID = q.Get("max_id")
+			case "prev":
+				pagination.PrevURL = target
+				pagination.MinID = q.Get("min_id")
+				pagination.SinceID = q.Get("since_id")
+			}
+		}
+	}
+
+	return pagination
+}
+
+// ErrNoMorePages is returned when a paginator has no page in the requested direction
+var ErrNoMorePages = errors.New("no more pages")
+
+// Paginator walks the pages of a list endpoint by following the Link header
+type Paginator[T any] struct {
+	client     *Client
+	pagination Pagination
+	wrap       func(error) error
+}
+
+// NewPaginator returns a paginator whose first call to Next requests the url
+func NewPaginator[T any](c *Client, url string) *Paginator[T] {
+	return &Paginator[T]{
+		client:     c,
+		pagination: Pagination{NextURL: url},
+	}
+}
+
+// HasNext reports whether there is a next (older) page
+func (p *Paginator[T]) HasNext() bool {
+	return p.pagination.HasNext()
+}
+
+// HasPrev reports whether there is a previous (newer) page
+func (p *Paginator[T]) HasPrev() bool {
+	return p.pagination.HasPrev()
+}
+
+// Pagination returns the paging information of the last page
+func (p *Paginator[T]) Pagination() Pagination {
+	return p.pagination
+}
+
+// Get the next (older) page, the first call returns the first page
+func (p *Paginator[T]) Next() (T, error) {
+	return p.NextContext(context.Background())
+}
+
+// Same as Next but the requests use the given context
+func (p *Paginator[T]) NextContext(ctx context.Context) (T, error) {
+	return p.fetch(ctx, p.pagination.NextURL)
+}
+
+// Get the previous (newer) page
+func (p *Paginator[T]) Prev() (T, error) {
+	return p.PrevContext(context.Background())
+}
+
+// Same as Prev but the requests use the given context
+func (p *Paginator[T]) PrevContext(ctx context.Context) (T, error) {
+	return p.fetch(ctx, p.pagination.PrevURL)
+}
+
+// Call fn for every page from the current position until there is no next
+// page. Walking stops when fn returns an error, which is then returned
+func (p *Paginator[T]) Each(ctx context.Context, fn func(T) error) error {
+	for p.HasNext() {
+		page, err := p.NextContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Request the page and update the links from its Link header
+func (p *Paginator[T]) fetch(ctx context.Context, url string) (T, error) {
+	var page T
+
+	if url == "" {
+		return page, ErrNoMorePages
+	}
+
+	// Links come from the server and must stay on it
+	url, err := p.client.serverURL(url)
+	if err != nil {
+		return page, err
+	}
+
+	body, header, err := p.client.sendRequest(ctx, url)
+	if err != nil {
+		if p.wrap != nil {
+			err = p.wrap(err)
+		}
+		return page, err
+	}
+
+	p.pagination = parseLinkHeader(header.Get("Link"))
+	err = json.Unmarshal(body, &page)
+
+	return page, err
+}
+
+// Get a paginator over the public timeline. The params are optional and may be nil
+func (c *Client) TimelinePublicPaginator(params *TimelineParams) *Paginator[Statuses] {
+	v := url.Values{}
+	params.encode(v)
+
+	return NewPaginator[Statuses](c, c.buildURL(TimelinesPublicURI, v))
+}
+
+// Get a paginator over the hashtag timeline. The params are optional and may be nil
+func (c *Client) TimelineTagPaginator(hashtag string, params *TagTimelineParams) *Paginator[Statuses] {
+	v := url.Values{}
+	params.encode(v)
+	uri := fmt.Sprintf(TimelinesTagURI, url.PathEscape(strings.TrimPrefix(hashtag, "#")))
+
+	return NewPaginator[Statuses](c, c.buildURL(uri, v))
+}
+
+// Get a paginator over the statuses of an account. The params are optional and may be nil
+func (c *Client) AccountStatusesPaginator(id string, params *AccountStatusesParams) *Paginator[Statuses] {
+	v := url.Values{}
+	params.encode(v)
+	uri := fmt.Sprintf(AccountStatusesURI, url.PathEscape(id))
+
+	return NewPaginator[Statuses](c, c.buildURL(uri, v))
+}
+
+// Get a paginator over the accounts that favourited a status. The params are optional and may be nil
+func (c *Client) StatusFavouritedByPaginator(id string, params *PageParams) *Paginator[Accounts] {
+	return c.statusAccountsPaginator(StatusFavouritedByURI, id, params)
+}
+
+// Get a paginator over the accounts that boosted a status. The params are optional and may be nil
+func (c *Client) StatusRebloggedByPaginator(id string, params *PageParams) *Paginator[Accounts] {
+	return c.statusAccountsPaginator(StatusRebloggedByURI, id, params)
+}
+
+// Get a paginator over the accounts that interacted with a status
+func (c *Client) statusAccountsPaginator(format string, id string, params *PageParams) *Paginator[Accounts] {
+	v := url.Values{}
+	if params != nil {
+		params.encode(v)
+	}
+	uri := fmt.Sprintf(format, url.PathEscape(id))
+
+	return NewPaginator[Accounts](c, c.buildURL(uri, v))
+}
+
+// Get a paginator over the peers of the instance. Mastodon returns every peer
+// in a single page, so the paginator ends after the first page
+func (c *Client) InstancePeersPaginator() *Paginator[InstancePeers] {
+	p := NewPaginator[InstancePeers](c, c.buildURL(InstancePeersURI, nil))
+	p.wrap = func(err error) error {
+		return restrictedEndpointError(InstancePeersURI, err)
+	}
+
+	return p
+}
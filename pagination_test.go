@@ -0,0 +1,163 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	header := `<https://mastodon.example/api/v1/statuses/1/reblogged_by?max_id=10>; rel="next", <https://mastodon.example/api/v1/statuses/1/reblogged_by?since_id=20>; rel="prev"`
+
+	pagination := parseLinkHeader(header)
+
+	if pagination.NextURL != "https://mastodon.example/api/v1/statuses/1/reblogged_by?max_id=10" {
+		t.Fatalf("next url was incorrectly set to: %s", pagination.NextURL)
+	}
+
+	if pagination.MaxID != "10" || pagination.SinceID != "20" || pagination.MinID != "" {
+		t.Fatalf("ids were incorrectly parsed: %+v", pagination)
+	}
+
+	empty := parseLinkHeader("")
+	if empty.HasNext() || empty.HasPrev() {
+		t.Fatalf("empty header should not have links: %+v", empty)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case TimelinesPublicURI:
+			if r.URL.Query().Get("local") != "true" {
+				t.Errorf("filters were not kept: %s", r.URL.RawQuery)
+			}
+
+			// Three pages of statuses with IDs 3, 2 and 1
+			maxID, _ := strconv.Atoi(r.URL.Query().Get("max_id"))
+			if maxID == 0 {
+				maxID = 4
+			}
+			id := maxID - 1
+			if id < 1 {
+				fmt.Fprintln(w, "[]")
+				return
+			}
+
+			links := []string{fmt.Sprintf(`<%s%s?local=true&min_id=%d>; rel="prev"`, ts.URL, TimelinesPublicURI, id)}
+			if id > 1 {
+				links = append(links, fmt.Sprintf(`<%s%s?local=true&max_id=%d>; rel="next"`, ts.URL, TimelinesPublicURI, id))
+			}
+			w.Header().Set("Link", strings.Join(links, ", "))
+			fmt.Fprintf(w, `[{"id": "%d"}]`+"\n", id)
+			return
+		case InstancePeersURI:
+			fmt.Fprintln(w, `["tilde.zone", "conf.tube"]`)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	paginator := client.TimelinePublicPaginator(&TimelineParams{Local: true})
+
+	var ids []string
+	err = paginator.Each(context.Background(), func(statuses Statuses) error {
+		for _, status := range statuses {
+			ids = append(ids, status.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if strings.Join(ids, ",") != "3,2,1" {
+		t.Fatalf("should have walked 3 pages instead got: %v", ids)
+	}
+
+	_, err = paginator.Next()
+	if !errors.Is(err, ErrNoMorePages) {
+		t.Fatalf("should have no more pages instead got: %v", err)
+	}
+
+	if !paginator.HasPrev() || paginator.Pagination().MinID != "1" {
+		t.Fatalf("should have a previous page: %+v", paginator.Pagination())
+	}
+
+	// Peers are returned in a single page
+	peers := client.InstancePeersPaginator()
+	page, err := peers.Next()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(page) != 2 || peers.HasNext() {
+		t.Fatalf("should have returned a single page of 2 peers: %v", page)
+	}
+}
+
+func TestPaginatorOtherHost(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://evil.example/api/v1/timelines/public?max_id=1>; rel="next"`)
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	p := client.TimelinePublicPaginator(nil)
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// The link to another host is never requested
+	if _, err := p.Next(); !errors.Is(err, ErrOtherHost) {
+		t.Fatalf("should be ErrOtherHost: %v", err)
+	}
+}
+
+func TestPaginatorBaseURL(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		// The server links to its public domain without the proxy prefix
+		w.Header().Set("Link", `<https://mastodon.example/api/v1/timelines/public?max_id=1>; rel="next"`)
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient("https://mastodon.example", WithBaseURL(ts.URL+"/mastodon"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	p := client.TimelinePublicPaginator(nil)
+	for i := 0; i < 2; i++ {
+		if _, err := p.Next(); err != nil {
+			t.Fatalf("should not be fail: %v", err)
+		}
+	}
+
+	expected := []string{"/mastodon/api/v1/timelines/public", "/mastodon/api/v1/timelines/public?max_id=1"}
+	if len(paths) != 2 || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Fatalf("links should be sent through the base url: %v", paths)
+	}
+}
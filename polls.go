@@ -0,0 +1,96 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	PollsURI string = "/api/v1/polls/%s"
+)
+
+// Poll hold information for a poll attached to a status
+type Poll struct {
+	ID          string       `json:"id"`
+	ExpiresAt   *time.Time   `json:"expires_at"`
+	Expired     bool         `json:"expired"`
+	Multiple    bool         `json:"multiple"`
+	VotesCount  int          `json:"votes_count"`
+	VotersCount *int         `json:"voters_count"`
+	Options     []PollOption `json:"options"`
+	Emojis      []Emoji      `json:"emojis"`
+}
+
+// PollOption hold information for a possible answer to a poll
+type PollOption struct {
+	Title      string `json:"title"`
+	VotesCount *int   `json:"votes_count"`
+}
+
+// PollsConfiguration hold the limits the server places on polls
+type PollsConfiguration struct {
+	MaxOptions             int `json:"max_options"`
+	MaxCharactersPerOption int `json:"max_characters_per_option"`
+	MinExpiration          int `json:"min_expiration"`
+	MaxExpiration          int `json:"max_expiration"`
+}
+
+// Check the poll against the limits of the server. The expiration is only
+// checked when createdAt, usually the CreatedAt of the status, is not zero
+func (p Poll) CheckLimits(config PollsConfiguration, createdAt time.Time) error {
+	if config.MaxOptions > 0 && len(p.Options) > config.MaxOptions {
+		return fmt.Errorf("poll has %d options, the server allows at most %d", len(p.Options), config.MaxOptions)
+	}
+
+	if config.MaxCharactersPerOption > 0 {
+		for _, option := range p.Options {
+			if n := utf8.RuneCountInString(option.Title); n > config.MaxCharactersPerOption {
+				return fmt.Errorf("poll option %q has %d characters, the server allows at most %d", option.Title, n, config.MaxCharactersPerOption)
+			}
+		}
+	}
+
+	if createdAt.IsZero() || p.ExpiresAt == nil {
+		return nil
+	}
+
+	expiration := int(p.ExpiresAt.Sub(createdAt).Seconds())
+	if config.MinExpiration > 0 && expiration < config.MinExpiration {
+		return fmt.Errorf("poll expires after %d seconds, the server requires at least %d", expiration, config.MinExpiration)
+	}
+	if config.MaxExpiration > 0 && expiration > config.MaxExpiration {
+		return fmt.Errorf("poll expires after %d seconds, the server allows at most %d", expiration, config.MaxExpiration)
+	}
+
+	return nil
+}
+
+// Get information about the poll with the given ID
+func (c *Client) GetPoll(id string) (Poll, error) {
+	return c.GetPollContext(context.Background(), id)
+}
+
+// Same as GetPoll but the requests use the given context
+func (c *Client) GetPollContext(ctx context.Context, id string) (Poll, error) {
+	poll := Poll{}
+
+	if err := c.require(FeaturePolls); err != nil {
+		return poll, err
+	}
+
+	uri := fmt.Sprintf(PollsURI, url.PathEscape(id))
+	endpoint := c.buildURL(uri, nil)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return poll, err
+	}
+
+	err = json.Unmarshal(body, &poll)
+
+	return poll, err
+}
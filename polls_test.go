@@ -0,0 +1,99 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testpoll string = `{
+		"id": "34830",
+		"expires_at": "2019-12-05T04:05:08.302Z",
+		"expired": true,
+		"multiple": false,
+		"votes_count": 10,
+		"voters_count": null,
+		"options": [
+		  {
+			"title": "accept",
+			"votes_count": 6
+		  },
+		  {
+			"title": "deny",
+			"votes_count": 4
+		  }
+		],
+		"emojis": []
+	  }`
+)
+
+func TestGetPoll(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(PollsURI, "34830"):
+			fmt.Fprintln(w, testpoll)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	poll, err := client.GetPoll("34830")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(poll.Options) != 2 || *poll.Options[0].VotesCount != 6 {
+		t.Fatalf("options were incorrectly parsed: %+v", poll.Options)
+	}
+
+	if poll.VotersCount != nil {
+		t.Fatalf("voters count should be nil for single choice polls")
+	}
+}
+
+func TestPollCheckLimits(t *testing.T) {
+	var instance Instance
+	err := json.Unmarshal([]byte(testinstance), &instance)
+	if err != nil {
+		t.Fatalf("error unmarshalling test instance: %v", err)
+	}
+
+	var poll Poll
+	err = json.Unmarshal([]byte(testpoll), &poll)
+	if err != nil {
+		t.Fatalf("error unmarshalling test poll: %v", err)
+	}
+
+	config := instance.Configuration.Polls
+	createdAt := poll.ExpiresAt.Add(-24 * time.Hour)
+
+	err = poll.CheckLimits(config, createdAt)
+	if err != nil {
+		t.Fatalf("should be within limits: %v", err)
+	}
+
+	err = poll.CheckLimits(config, poll.ExpiresAt.Add(-time.Minute))
+	if err == nil {
+		t.Fatalf("should fail for an expiration below the minimum")
+	}
+
+	poll.Options = append(poll.Options, PollOption{Title: "a"}, PollOption{Title: "b"}, PollOption{Title: "c"})
+	err = poll.CheckLimits(config, time.Time{})
+	if err == nil {
+		t.Fatalf("should fail for more than %d options", config.MaxOptions)
+	}
+}
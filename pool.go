@@ -0,0 +1,101 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Default number of servers queried at the same time by a Pool
+const PoolConcurrency int = 10
+
+// Pool runs the same request against many servers with bounded concurrency
+type Pool struct {
+	clients     []*Client
+	concurrency int
+	hostTimeout time.Duration
+}
+
+// PoolResult hold the value or error returned by a single server
+type PoolResult[T any] struct {
+	Server string
+	Value  T
+	Err    error
+}
+
+// NewPool returns a pool with a client for each server created with the
+// options. At most concurrency servers are queried at the same time, using
+// PoolConcurrency when zero, and each server is given at most hostTimeout to
+// answer, which includes retries, or no limit beyond the client timeout when zero
+func NewPool(servers []string, concurrency int, hostTimeout time.Duration, opts ...Option) (*Pool, error) {
+	if concurrency < 0 {
+		return nil, errors.New("concurrency can not be negative")
+	}
+	if concurrency == 0 {
+		concurrency = PoolConcurrency
+	}
+	if hostTimeout < 0 {
+		return nil, errors.New("host timeout can not be negative")
+	}
+
+	p := &Pool{
+		concurrency: concurrency,
+		hostTimeout: hostTimeout,
+	}
+
+	for _, server := range servers {
+		c, err := NewClient(server, opts...)
+		if err != nil {
+			return nil, err
+		}
+		p.clients = append(p.clients, c)
+	}
+
+	return p, nil
+}
+
+// Clients returns the clients of the pool in the order of the servers
+func (p *Pool) Clients() []*Client {
+	return append([]*Client(nil), p.clients...)
+}
+
+// FanOut calls fn with the client of every server in the pool and returns
+// the results in the order of the servers. Method expressions can be used
+// directly, for example FanOut(ctx, pool, (*Client).GetInstanceDataContext)
+func FanOut[T any](ctx context.Context, p *Pool, fn func(*Client, context.Context) (T, error)) []PoolResult[T] {
+	results := make([]PoolResult[T], len(p.clients))
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+
+	for i, c := range p.clients {
+		results[i].Server = c.Server
+
+		// Wait for a free slot unless the context is done
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hostctx := ctx
+			if p.hostTimeout > 0 {
+				var cancel context.CancelFunc
+				hostctx, cancel = context.WithTimeout(ctx, p.hostTimeout)
+				defer cancel()
+			}
+
+			results[i].Value, results[i].Err = fn(c, hostctx)
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	return results
+}
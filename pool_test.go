@@ -0,0 +1,106 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	var mu sync.Mutex
+	active, maxactive := 0, 0
+
+	handler := func(peers string, delay time.Duration) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			active++
+			if active > maxactive {
+				maxactive = active
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(delay):
+			}
+
+			if peers == "" {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintln(w, peers)
+		}
+	}
+
+	// Setup servers, the last one answers after the host timeout
+	servers := []string{}
+	for _, h := range []http.HandlerFunc{
+		handler(`["a.example"]`, 20*time.Millisecond),
+		handler(`["a.example", "b.example"]`, 20*time.Millisecond),
+		handler("", 20*time.Millisecond),
+		handler(`["c.example"]`, time.Second),
+	} {
+		ts := httptest.NewServer(h)
+		defer ts.Close()
+		servers = append(servers, ts.URL)
+	}
+
+	pool, err := NewPool(servers, 2, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	results := FanOut(context.Background(), pool, (*Client).GetInstancePeersContext)
+	if len(results) != 4 {
+		t.Fatalf("should have returned 4 results but instead returned: %d", len(results))
+	}
+
+	for i, result := range results {
+		if result.Server != servers[i] {
+			t.Fatalf("results should be in server order: %s", result.Server)
+		}
+	}
+
+	if results[0].Err != nil || len(results[1].Value) != 2 {
+		t.Fatalf("should have returned the peers: %+v", results[:2])
+	}
+	if !errors.Is(results[2].Err, ErrEndpointDisabled) {
+		t.Fatalf("should be disabled instead got: %v", results[2].Err)
+	}
+	if !errors.Is(results[3].Err, context.DeadlineExceeded) {
+		t.Fatalf("should exceed the host timeout instead got: %v", results[3].Err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxactive > 2 {
+		t.Fatalf("should query at most 2 servers at once but queried: %d", maxactive)
+	}
+}
+
+func TestNewPool(t *testing.T) {
+	_, err := NewPool([]string{"https://a.example", "b.example"}, 0, 0)
+	if err == nil {
+		t.Fatalf("should fail for an invalid server")
+	}
+
+	pool, err := NewPool([]string{"https://a.example"}, 0, 0, WithUserAgent("crawler/1.0"))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	if pool.Clients()[0].UserAgent != "crawler/1.0" {
+		t.Fatalf("options were not applied to the clients")
+	}
+}
@@ -0,0 +1,261 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limit headers returned by Mastodon
+const (
+	RateLimitLimitHeader     string = "X-RateLimit-Limit"
+	RateLimitRemainingHeader string = "X-RateLimit-Remaining"
+	RateLimitResetHeader     string = "X-RateLimit-Reset"
+
+	// Longest wait for the rate limit to reset, Mastodon resets every 5 minutes
+	RateLimitMaxWait = 5 * time.Minute
+)
+
+// RateLimit hold the request budget reported by the server
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Known reports whether the server has reported a rate limit yet
+func (r RateLimit) Known() bool {
+	return r.Limit > 0 || !r.Reset.IsZero()
+}
+
+// Exhausted reports whether no requests remain before the reset time
+func (r RateLimit) Exhausted(now time.Time) bool {
+	return r.Known() && r.Remaining <= 0 && r.Reset.After(now)
+}
+
+// Track the rate limit of the server across requests
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   RateLimit
+	wait    bool
+	maxWait time.Duration
+}
+
+// Update the rate limit from the response headers
+func (r *rateLimiter) update(header http.Header) {
+	if r == nil || header == nil {
+		return
+	}
+
+	limit, errlimit := strconv.Atoi(header.Get(RateLimitLimitHeader))
+	remaining, errremaining := strconv.Atoi(header.Get(RateLimitRemainingHeader))
+	reset, okreset := parseRateLimitReset(header.Get(RateLimitResetHeader), time.Now())
+	if errlimit != nil && errremaining != nil && !okreset {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if errlimit == nil {
+		r.limit.Limit = limit
+	}
+	if errremaining == nil {
+		r.limit.Remaining = remaining
+	}
+	if okreset {
+		r.limit.Reset = reset
+	}
+}
+
+// Get the current rate limit
+func (r *rateLimiter) get() RateLimit {
+	if r == nil {
+		return RateLimit{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.limit
+}
+
+// Report whether requests should wait for the rate limit to reset
+func (r *rateLimiter) waiting() bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.wait
+}
+
+// Get the time until the reset and whether it is known and short enough to wait for
+func (r *rateLimiter) untilReset(now time.Time) (time.Duration, bool) {
+	limit := r.get()
+	if !limit.Known() || limit.Reset.IsZero() {
+		return 0, false
+	}
+
+	r.mu.Lock()
+	maxwait := r.maxWait
+	r.mu.Unlock()
+	if maxwait <= 0 {
+		maxwait = RateLimitMaxWait
+	}
+
+	d := limit.Reset.Sub(now)
+
+	return d, d <= maxwait
+}
+
+// Block until the reset time if the budget is exhausted. Resets further away
+// than the maximum wait are not waited for, the server then answers with 429
+func (r *rateLimiter) waitReset(ctx context.Context) error {
+	now := time.Now()
+	if !r.get().Exhausted(now) {
+		return nil
+	}
+
+	d, ok := r.untilReset(now)
+	if !ok {
+		return nil
+	}
+
+	return sleepContext(ctx, d)
+}
+
+// Parse the reset header which Mastodon sends as an ISO 8601 timestamp.
+// Unix timestamps and seconds until the reset are accepted for other servers
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if reset, err := time.Parse(time.RFC3339, value); err == nil {
+		return reset, true
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// Values this large can only be unix timestamps
+	if seconds > 1000000000 {
+		return time.Unix(seconds, 0), true
+	}
+
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
+
+// Sleep for the duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimit returns the request budget last reported by the server
+func (c *Client) RateLimit() RateLimit {
+	return c.ratelimit.get()
+}
+
+// WithRateLimitWait makes the client wait for the reset time before sending a
+// request when the budget is exhausted, and retry a request once after a 429
+// response when the server reported when the limit resets. Resets further away
+// than RateLimitMaxWait are not waited for
+func WithRateLimitWait() Option {
+	return func(c *Client) error {
+		c.ratelimit.wait = true
+
+		return nil
+	}
+}
+
+// WithRateLimitMaxWait changes the longest wait for the rate limit to reset
+// when WithRateLimitWait is used
+func WithRateLimitMaxWait(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("rate limit maximum wait must be positive")
+		}
+		c.ratelimit.maxWait = d
+
+		return nil
+	}
+}
+
+// Space out the requests to each host by a fixed delay
+type pacer struct {
+	mu    sync.Mutex
+	delay time.Duration
+	next  map[string]time.Time
+}
+
+// Create a pacer that waits delay between requests to the same host
+func newPacer(delay time.Duration) *pacer {
+	return &pacer{
+		delay: delay,
+		next:  map[string]time.Time{},
+	}
+}
+
+// Block until a request may be sent to the host. Every call reserves the
+// next slot, so concurrent requests to the same host are spaced out too
+func (p *pacer) wait(ctx context.Context, host string) error {
+	if p == nil || p.delay <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	host = strings.ToLower(host)
+
+	p.mu.Lock()
+	slot := p.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next[host] = slot.Add(p.delay)
+	p.mu.Unlock()
+
+	return sleepContext(ctx, slot.Sub(now))
+}
+
+// WithRequestDelay waits at least the delay between requests to the server,
+// including retries
+func WithRequestDelay(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return errors.New("request delay can not be negative")
+		}
+		c.pacer = newPacer(d)
+
+		return nil
+	}
+}
+
+// Share a pacer between clients, such as all the clients of a crawl
+func withPacer(p *pacer) Option {
+	return func(c *Client) error {
+		c.pacer = p
+
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RateLimitLimitHeader, "300")
+		w.Header().Set(RateLimitRemainingHeader, "299")
+		w.Header().Set(RateLimitResetHeader, reset.Format(time.RFC3339))
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if client.RateLimit().Known() {
+		t.Fatalf("rate limit should not be known before a request")
+	}
+
+	_, err = client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	limit := client.RateLimit()
+	if limit.Limit != 300 || limit.Remaining != 299 || !limit.Reset.Equal(reset) {
+		t.Fatalf("rate limit was incorrectly parsed: %+v", limit)
+	}
+
+	if limit.Exhausted(time.Now()) {
+		t.Fatalf("rate limit should not be exhausted")
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	requests := 0
+	var reset time.Time
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set(RateLimitLimitHeader, "2")
+
+		switch requests {
+		case 1:
+			// Exhaust the budget
+			reset = time.Now().Add(200 * time.Millisecond)
+			w.Header().Set(RateLimitRemainingHeader, "0")
+			w.Header().Set(RateLimitResetHeader, reset.Format(time.RFC3339Nano))
+			fmt.Fprintln(w, "[]")
+		case 2:
+			if time.Now().Before(reset) {
+				t.Errorf("request was sent before the reset time")
+			}
+			// Rate limited anyway, reset shortly
+			reset = time.Now().Add(200 * time.Millisecond)
+			w.Header().Set(RateLimitRemainingHeader, "0")
+			w.Header().Set(RateLimitResetHeader, reset.Format(time.RFC3339Nano))
+			http.Error(w, `{"error":"Too many requests"}`, http.StatusTooManyRequests)
+		default:
+			if time.Now().Before(reset) {
+				t.Errorf("retry was sent before the reset time")
+			}
+			w.Header().Set(RateLimitRemainingHeader, "1")
+			fmt.Fprintln(w, "[]")
+		}
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL, WithRateLimitWait())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if !client.RateLimit().Exhausted(time.Now()) {
+		t.Fatalf("rate limit should be exhausted: %+v", client.RateLimit())
+	}
+
+	_, err = client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail after waiting: %v", err)
+	}
+
+	if requests != 3 {
+		t.Fatalf("should have sent 3 requests but instead sent: %d", requests)
+	}
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1668800000, 0)
+
+	reset, ok := parseRateLimitReset("2022-11-19T00:40:00.000Z", now)
+	if !ok || !reset.Equal(time.Date(2022, 11, 19, 0, 40, 0, 0, time.UTC)) {
+		t.Fatalf("iso 8601 reset was incorrectly parsed: %s", reset)
+	}
+
+	reset, ok = parseRateLimitReset("1668801000", now)
+	if !ok || !reset.Equal(time.Unix(1668801000, 0)) {
+		t.Fatalf("unix reset was incorrectly parsed: %s", reset)
+	}
+
+	reset, ok = parseRateLimitReset("30", now)
+	if !ok || !reset.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("seconds reset was incorrectly parsed: %s", reset)
+	}
+
+	_, ok = parseRateLimitReset("soon", now)
+	if ok {
+		t.Fatalf("invalid reset should not be parsed")
+	}
+}
+
+func TestRateLimitWaitTooLong(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Rate limited until a reset far in the future
+		w.Header().Set(RateLimitLimitHeader, "300")
+		w.Header().Set(RateLimitRemainingHeader, "0")
+		w.Header().Set(RateLimitResetHeader, time.Now().Add(time.Hour).Format(time.RFC3339))
+		http.Error(w, `{"error":"Too many requests"}`, http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL, WithRateLimitWait(), WithRateLimitMaxWait(time.Second))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.GetCustomEmojis()
+	if !IsRateLimited(err) {
+		t.Fatalf("should be rate limited: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("should not wait for a reset beyond the maximum wait, waited %s", elapsed)
+	}
+
+	// The exhausted budget is not waited for either
+	_, err = client.GetCustomEmojis()
+	if !IsRateLimited(err) {
+		t.Fatalf("should be rate limited: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("should not wait for a reset beyond the maximum wait, waited %s", elapsed)
+	}
+
+	if requests != 2 {
+		t.Fatalf("should have sent 2 requests but instead sent: %d", requests)
+	}
+
+	if _, err := NewClient(ts.URL, WithRateLimitMaxWait(0)); err == nil {
+		t.Fatalf("zero maximum wait should fail")
+	}
+}
@@ -0,0 +1,166 @@
+package mastodon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy hold the settings for retrying failed requests
+type RetryPolicy struct {
+	// Total number of attempts, including the first request
+	MaxAttempts int
+	// Delay before the first retry, doubled for every following retry
+	BaseDelay time.Duration
+	// Upper bound of the delay between attempts, no bound when zero. Requests
+	// are not retried when the server asks to wait longer with Retry-After
+	MaxDelay time.Duration
+	// Fraction of the delay, between 0 and 1, that is randomised
+	Jitter float64
+	// Decide if a request should be retried from the status code, 0 for
+	// transport errors, and the error. DefaultShouldRetry is used when nil
+	ShouldRetry func(statusCode int, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy that makes up to 3 attempts starting
+// with a half second delay
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// DefaultShouldRetry retries transport errors, timeouts and the 408, 429,
+// 500, 502, 503 and 504 status codes. Cancelled contexts are never retried
+func DefaultShouldRetry(statusCode int, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch statusCode {
+	case 0:
+		// Servers that do not exist will not appear on a retry
+		var dnserr *net.DNSError
+		if errors.As(err, &dnserr) && dnserr.IsNotFound {
+			return false
+		}
+
+		var neterr net.Error
+		if errors.As(err, &neterr) && neterr.Timeout() {
+			return true
+		}
+
+		// Connections refused, reset or closed by small instances under load
+		var operr *net.OpError
+		if errors.As(err, &operr) {
+			return true
+		}
+
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// Report whether the request should be attempted again
+func (p *RetryPolicy) retry(attempt int, statusCode int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(statusCode, err)
+	}
+
+	return DefaultShouldRetry(statusCode, err)
+}
+
+// Get the delay before the next attempt. The Retry-After header of the
+// response takes precedence over the exponential backoff, and false is
+// returned when it is longer than the maximum delay
+func (p *RetryPolicy) delay(attempt int, header http.Header) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			return 0, false
+		}
+		return d, true
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay) && d < math.MaxInt64/4; i++ {
+		d *= 2
+	}
+
+	// Jitter before the clamp so the maximum delay is never exceeded
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d = time.Duration(float64(d) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	return d, true
+}
+
+// Parse the Retry-After header which is either seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	d := date.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+
+	return d, true
+}
+
+// WithRetryPolicy retries failed requests according to the policy. When
+// WithRateLimitWait is also used, 429 responses are only retried by the rate limiter
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy.MaxAttempts < 1 {
+			return errors.New("retry policy needs at least 1 attempt")
+		}
+		if policy.BaseDelay < 0 || policy.MaxDelay < 0 || policy.Jitter < 0 {
+			return errors.New("retry policy delays and jitter can not be negative")
+		}
+		c.retry = &policy
+
+		return nil
+	}
+}
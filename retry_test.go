@@ -0,0 +1,175 @@
+package mastodon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	requests := map[string]int{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		// Return based on URI
+		switch r.URL.Path {
+		case InstancePeersURI:
+			if requests[r.URL.Path] < 3 {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, `["tilde.zone"]`)
+			return
+		case InstanceRulesURI:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+	}
+	client, err := NewClient(ts.URL, WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// Succeeds on the third attempt
+	_, err = client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if requests[InstancePeersURI] != 3 {
+		t.Fatalf("should have sent 3 requests but instead sent: %d", requests[InstancePeersURI])
+	}
+
+	// Gives up after the maximum attempts
+	_, err = client.GetInstanceRules()
+	var apierr *APIError
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("should return the last error instead got: %v", err)
+	}
+	if requests[InstanceRulesURI] != 3 {
+		t.Fatalf("should have sent 3 requests but instead sent: %d", requests[InstanceRulesURI])
+	}
+
+	// Not found is not retried
+	_, err = client.GetCustomEmojis()
+	if !IsNotFound(err) || requests[CustomEmojisURI] != 1 {
+		t.Fatalf("should not retry not found: %v (%d requests)", err, requests[CustomEmojisURI])
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+
+	delays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, expected := range delays {
+		if d, _ := policy.delay(i+1, nil); d != expected {
+			t.Fatalf("attempt %d should wait %s instead of %s", i+1, expected, d)
+		}
+	}
+
+	// Jitter never exceeds the maximum delay
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d, _ := policy.delay(10, nil); d > time.Second {
+			t.Fatalf("jittered delay exceeds the maximum delay: %s", d)
+		}
+	}
+
+	// Jitter stays within the fraction of the delay
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d, _ := policy.delay(1, nil); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jittered delay out of range: %s", d)
+		}
+	}
+
+	// Retry-After takes precedence
+	policy.MaxDelay = 10 * time.Second
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	if d, ok := policy.delay(1, header); !ok || d != 7*time.Second {
+		t.Fatalf("should honour Retry-After instead waited: %s", d)
+	}
+
+	// Retry-After longer than the maximum delay stops retrying
+	header.Set("Retry-After", "3600")
+	if _, ok := policy.delay(1, header); ok {
+		t.Fatalf("should not retry when Retry-After exceeds the maximum delay")
+	}
+
+	// Delays double without bound when there is no maximum delay
+	unbounded := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second}
+	for i, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if d, _ := unbounded.delay(i+1, nil); d != expected {
+			t.Fatalf("attempt %d should wait %s instead of %s", i+1, expected, d)
+		}
+	}
+
+	now := time.Date(2022, 11, 19, 0, 0, 0, 0, time.UTC)
+	d, ok := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	if !ok || d != time.Minute {
+		t.Fatalf("http date was incorrectly parsed: %s", d)
+	}
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	if !DefaultShouldRetry(http.StatusServiceUnavailable, &APIError{StatusCode: http.StatusServiceUnavailable}) {
+		t.Fatalf("should retry service unavailable")
+	}
+
+	if DefaultShouldRetry(http.StatusForbidden, &APIError{StatusCode: http.StatusForbidden}) {
+		t.Fatalf("should not retry forbidden")
+	}
+
+	if DefaultShouldRetry(0, errors.New("invalid character")) {
+		t.Fatalf("should not retry errors that are not transport errors")
+	}
+
+	_, err := NewClient("https://mastodon.example", WithRetryPolicy(RetryPolicy{}))
+	if err == nil {
+		t.Fatalf("should fail for a policy without attempts")
+	}
+}
+
+func TestRetryPolicyRateLimitWait(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set(RateLimitLimitHeader, "300")
+		w.Header().Set(RateLimitRemainingHeader, "0")
+		w.Header().Set(RateLimitResetHeader, time.Now().Add(10*time.Millisecond).Format(time.RFC3339Nano))
+		http.Error(w, `{"error":"Too many requests"}`, http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	client, err := NewClient(ts.URL, WithRetryPolicy(policy), WithRateLimitWait())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The rate limiter retries once and the retry policy does not retry again
+	_, err = client.GetCustomEmojis()
+	if !IsRateLimited(err) || requests != 2 {
+		t.Fatalf("should be rate limited after 2 requests: %v (%d requests)", err, requests)
+	}
+}
@@ -0,0 +1,221 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	StatusesURI           string = "/api/v1/statuses/%s"
+	StatusContextURI      string = "/api/v1/statuses/%s/context"
+	StatusFavouritedByURI string = "/api/v1/statuses/%s/favourited_by"
+	StatusRebloggedByURI  string = "/api/v1/statuses/%s/reblogged_by"
+)
+
+// Status visibility values
+const (
+	VisibilityPublic   string = "public"
+	VisibilityUnlisted string = "unlisted"
+	VisibilityPrivate  string = "private"
+	VisibilityDirect   string = "direct"
+)
+
+// Status hold information for a status posted by an account
+type Status struct {
+	ID                 string            `json:"id"`
+	URI                string            `json:"uri"`
+	URL                string            `json:"url"`
+	CreatedAt          time.Time         `json:"created_at"`
+	EditedAt           *time.Time        `json:"edited_at"`
+	Account            Account           `json:"account"`
+	Content            string            `json:"content"`
+	Text               string            `json:"text,omitempty"`
+	Visibility         string            `json:"visibility"`
+	Sensitive          bool              `json:"sensitive"`
+	SpoilerText        string            `json:"spoiler_text"`
+	Language           string            `json:"language"`
+	InReplyToID        string            `json:"in_reply_to_id"`
+	InReplyToAccountID string            `json:"in_reply_to_account_id"`
+	RepliesCount       int               `json:"replies_count"`
+	ReblogsCount       int               `json:"reblogs_count"`
+	FavouritesCount    int               `json:"favourites_count"`
+	MediaAttachments   []MediaAttachment `json:"media_attachments"`
+	Mentions           []Mention         `json:"mentions"`
+	Tags               []Tag             `json:"tags"`
+	Emojis             []Emoji           `json:"emojis"`
+	Card               *PreviewCard      `json:"card"`
+	Poll               *Poll             `json:"poll"`
+	Reblog             *Status           `json:"reblog"`
+	Application        *Application      `json:"application,omitempty"`
+}
+
+// Context hold the statuses above and below a status in a thread
+type Context struct {
+	Ancestors   Statuses `json:"ancestors"`
+	Descendants Statuses `json:"descendants"`
+}
+
+// MediaAttachment hold information for a file attached to a status
+type MediaAttachment struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	PreviewURL  string `json:"preview_url"`
+	RemoteURL   string `json:"remote_url"`
+	Description string `json:"description"`
+	Blurhash    string `json:"blurhash"`
+	Meta        struct {
+		Length   string  `json:"length,omitempty"`
+		Duration float64 `json:"duration,omitempty"`
+		Original struct {
+			Width  int     `json:"width,omitempty"`
+			Height int     `json:"height,omitempty"`
+			Size   string  `json:"size,omitempty"`
+			Aspect float64 `json:"aspect,omitempty"`
+		} `json:"original"`
+		Small struct {
+			Width  int     `json:"width,omitempty"`
+			Height int     `json:"height,omitempty"`
+			Size   string  `json:"size,omitempty"`
+			Aspect float64 `json:"aspect,omitempty"`
+		} `json:"small"`
+		Focus *struct {
+			X float64 `json:"x"`
+			Y float64 `json:"y"`
+		} `json:"focus,omitempty"`
+	} `json:"meta"`
+}
+
+// Mention hold information for an account mentioned in a status
+type Mention struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	URL      string `json:"url"`
+	Acct     string `json:"acct"`
+}
+
+// Tag hold information for a hashtag used within a status
+type Tag struct {
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	History   []TrendHistory `json:"history,omitempty"`
+	Following bool           `json:"following,omitempty"`
+}
+
+// PreviewCard hold information for a rich preview card of a link
+type PreviewCard struct {
+	URL          string         `json:"url"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Type         string         `json:"type"`
+	AuthorName   string         `json:"author_name"`
+	AuthorURL    string         `json:"author_url"`
+	ProviderName string         `json:"provider_name"`
+	ProviderURL  string         `json:"provider_url"`
+	HTML         string         `json:"html"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	Image        string         `json:"image"`
+	EmbedURL     string         `json:"embed_url"`
+	Blurhash     string         `json:"blurhash"`
+	History      []TrendHistory `json:"history,omitempty"`
+}
+
+// Application hold information for the application used to post a status
+type Application struct {
+	Name    string `json:"name"`
+	Website string `json:"website"`
+}
+
+// Get information about the status with the given ID
+func (c *Client) GetStatus(id string) (Status, error) {
+	return c.GetStatusContext(context.Background(), id)
+}
+
+// Same as GetStatus but the requests use the given context
+func (c *Client) GetStatusContext(ctx context.Context, id string) (Status, error) {
+	status := Status{}
+
+	uri := fmt.Sprintf(StatusesURI, url.PathEscape(id))
+	endpoint := c.buildURL(uri, nil)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return status, err
+	}
+
+	err = json.Unmarshal(body, &status)
+
+	return status, err
+}
+
+// Get the parent and child statuses in the thread of the status with the
+// given ID from the /api/v1/statuses/:id/context endpoint (StatusContextURI).
+// The method is named after the thread to avoid clashing with GetStatusContext,
+// which is the context.Context variant of GetStatus
+func (c *Client) GetStatusThread(id string) (Context, error) {
+	return c.GetStatusThreadContext(context.Background(), id)
+}
+
+// Same as GetStatusThread but the requests use the given context.Context
+func (c *Client) GetStatusThreadContext(ctx context.Context, id string) (Context, error) {
+	statuscontext := Context{}
+
+	uri := fmt.Sprintf(StatusContextURI, url.PathEscape(id))
+	endpoint := c.buildURL(uri, nil)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return statuscontext, err
+	}
+
+	err = json.Unmarshal(body, &statuscontext)
+
+	return statuscontext, err
+}
+
+// Get the accounts that favourited the status with the given ID. The params are optional and may be nil
+func (c *Client) GetStatusFavouritedBy(id string, params *PageParams) (Accounts, Pagination, error) {
+	return c.GetStatusFavouritedByContext(context.Background(), id, params)
+}
+
+// Same as GetStatusFavouritedBy but the requests use the given context
+func (c *Client) GetStatusFavouritedByContext(ctx context.Context, id string, params *PageParams) (Accounts, Pagination, error) {
+	return c.getStatusAccounts(ctx, StatusFavouritedByURI, id, params)
+}
+
+// Get the accounts that boosted the status with the given ID. The params are optional and may be nil
+func (c *Client) GetStatusRebloggedBy(id string, params *PageParams) (Accounts, Pagination, error) {
+	return c.GetStatusRebloggedByContext(context.Background(), id, params)
+}
+
+// Same as GetStatusRebloggedBy but the requests use the given context
+func (c *Client) GetStatusRebloggedByContext(ctx context.Context, id string, params *PageParams) (Accounts, Pagination, error) {
+	return c.getStatusAccounts(ctx, StatusRebloggedByURI, id, params)
+}
+
+// Get a page of accounts that interacted with a status
+func (c *Client) getStatusAccounts(ctx context.Context, format string, id string, params *PageParams) (Accounts, Pagination, error) {
+	accounts := Accounts{}
+	pagination := Pagination{}
+
+	v := url.Values{}
+	if params != nil {
+		params.encode(v)
+	}
+	uri := fmt.Sprintf(format, url.PathEscape(id))
+	endpoint := c.buildURL(uri, v)
+
+	body, header, err := c.sendRequest(ctx, endpoint)
+	if err != nil {
+		return accounts, pagination, err
+	}
+
+	pagination = parseLinkHeader(header.Get("Link"))
+	err = json.Unmarshal(body, &accounts)
+
+	return accounts, pagination, err
+}
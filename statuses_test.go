@@ -0,0 +1,144 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup Statuses
+		var statuses Statuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(StatusesURI, "108910940413327534"):
+			body, err := json.Marshal(statuses[0])
+			if err != nil {
+				t.Fatalf("error marshalling test status: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	status, err := client.GetStatus("108910940413327534")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if status.ID != "108910940413327534" {
+		t.Fatalf("id was incorrectly set to: %s", status.ID)
+	}
+}
+
+func TestGetStatusThread(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup Statuses
+		var statuses Statuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(StatusContextURI, "1"):
+			context := Context{
+				Ancestors:   statuses,
+				Descendants: append(statuses, statuses...),
+			}
+			body, err := json.Marshal(context)
+			if err != nil {
+				t.Fatalf("error marshalling test context: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	context, err := client.GetStatusThread("1")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(context.Ancestors) != 1 || len(context.Descendants) != 2 {
+		t.Fatalf("should have returned 1 ancestor and 2 descendants but instead returned: %d and %d", len(context.Ancestors), len(context.Descendants))
+	}
+}
+
+func TestGetStatusFavouritedBy(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(StatusFavouritedByURI, "1"), fmt.Sprintf(StatusRebloggedByURI, "1"):
+			if r.URL.Query().Get("limit") != "1" {
+				t.Errorf("invalid limit: %s", r.URL.RawQuery)
+			}
+			link := fmt.Sprintf(`<%s%s?max_id=243>; rel="next", <%s%s?min_id=245>; rel="prev"`, ts.URL, r.URL.Path, ts.URL, r.URL.Path)
+			w.Header().Set("Link", link)
+			fmt.Fprintf(w, "[%s]\n", testaccount)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	accounts, pagination, err := client.GetStatusFavouritedBy("1", &PageParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(accounts) != 1 {
+		t.Fatalf("should have returned 1 account but instead returned: %d", len(accounts))
+	}
+
+	if pagination.MaxID != "243" || pagination.MinID != "245" {
+		t.Fatalf("pagination was incorrectly parsed: %+v", pagination)
+	}
+
+	_, pagination, err = client.GetStatusRebloggedBy("1", &PageParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if !pagination.HasNext() || pagination.Next().MaxID != "243" {
+		t.Fatalf("pagination was incorrectly parsed: %+v", pagination)
+	}
+}
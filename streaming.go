@@ -0,0 +1,336 @@
+package mastodon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	StreamingURI string = "/api/v1/streaming"
+
+	StreamPublic       string = "public"
+	StreamPublicLocal  string = "public:local"
+	StreamPublicRemote string = "public:remote"
+	StreamHashtag      string = "hashtag"
+	StreamHashtagLocal string = "hashtag:local"
+
+	// Delays between reconnects, doubled after every failed attempt
+	StreamReconnectDelay    = time.Second
+	StreamMaxReconnectDelay = time.Minute
+
+	// Maximum size of a single streaming event
+	maxStreamEventSize = 4 * 1024 * 1024
+)
+
+// Stream hold the name of a streaming timeline and the hashtag for hashtag streams
+type Stream struct {
+	Name string
+	Tag  string
+}
+
+// Check that the stream is a public stream and has a tag when required
+func (s Stream) validate() error {
+	switch s.Name {
+	case StreamPublic, StreamPublicLocal, StreamPublicRemote:
+		return nil
+	case StreamHashtag, StreamHashtagLocal:
+		if strings.TrimPrefix(s.Tag, "#") == "" {
+			return fmt.Errorf("stream %s requires a tag", s.Name)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported stream: %s", s.Name)
+}
+
+// Get the stream as it is named in the events of the server
+func (s Stream) names() []string {
+	if s.Tag != "" {
+		return []string{s.Name, strings.TrimPrefix(s.Tag, "#")}
+	}
+
+	return []string{s.Name}
+}
+
+// Event is implemented by every event delivered by a stream
+type Event interface {
+	// Name of the event such as update or delete
+	EventName() string
+}
+
+// UpdateEvent is sent when a new status appears in the stream
+type UpdateEvent struct {
+	Stream []string
+	Status Status
+}
+
+// StatusUpdateEvent is sent when a status in the stream has been edited
+type StatusUpdateEvent struct {
+	Stream []string
+	Status Status
+}
+
+// DeleteEvent is sent when a status in the stream has been deleted
+type DeleteEvent struct {
+	Stream []string
+	ID     string
+}
+
+// UnknownEvent hold events that have no typed model, such as announcements
+type UnknownEvent struct {
+	Stream  []string
+	Name    string
+	Payload string
+}
+
+// ErrorEvent is sent when the connection failed, the stream reconnects afterwards
+type ErrorEvent struct {
+	Err error
+}
+
+// EventName returns update
+func (e *UpdateEvent) EventName() string { return "update" }
+
+// EventName returns status.update
+func (e *StatusUpdateEvent) EventName() string { return "status.update" }
+
+// EventName returns delete
+func (e *DeleteEvent) EventName() string { return "delete" }
+
+// EventName returns the name sent by the server
+func (e *UnknownEvent) EventName() string { return e.Name }
+
+// EventName returns error
+func (e *ErrorEvent) EventName() string { return "error" }
+
+// Decode the payload of a streaming event
+func decodeEvent(name string, payload string, stream []string) (Event, error) {
+	switch name {
+	case "update":
+		event := &UpdateEvent{Stream: stream}
+		err := json.Unmarshal([]byte(payload), &event.Status)
+		return event, err
+	case "status.update":
+		event := &StatusUpdateEvent{Stream: stream}
+		err := json.Unmarshal([]byte(payload), &event.Status)
+		return event, err
+	case "delete":
+		// The ID may be sent as a JSON string or as plain text
+		id := payload
+		var quoted string
+		if json.Unmarshal([]byte(payload), &quoted) == nil {
+			id = quoted
+		}
+		return &DeleteEvent{Stream: stream, ID: id}, nil
+	}
+
+	return &UnknownEvent{Stream: stream, Name: name, Payload: payload}, nil
+}
+
+// Get the base URL of the streaming server, discovering it from the instance
+// configuration when it was not set with WithStreamingURL. Websocket schemes
+// are changed to http as both transports connect with the http client
+func (c *Client) streamingURL(ctx context.Context) (string, error) {
+	streaming := c.streaming
+	if streaming == "" {
+		instance, err := c.GetInstanceDataContext(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		streaming = instance.Configuration.Urls.Streaming
+		if streaming == "" {
+			streaming = c.Server
+		}
+	}
+
+	u, err := url.Parse(streaming)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "wss":
+		u.Scheme = "https"
+	case "ws":
+		u.Scheme = "http"
+	}
+
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// Get an http client for long lived connections, the timeout of the client
+// would otherwise close the stream
+func (c *Client) streamingClient() *http.Client {
+	hc := c.Client
+	hc.Timeout = 0
+
+	return &hc
+}
+
+// Wait before the next reconnect and return the following delay
+func reconnectDelay(ctx context.Context, delay time.Duration) (time.Duration, error) {
+	if err := sleepContext(ctx, delay); err != nil {
+		return delay, err
+	}
+
+	delay *= 2
+	if delay > StreamMaxReconnectDelay {
+		delay = StreamMaxReconnectDelay
+	}
+
+	return delay, nil
+}
+
+// StreamSSE connects to a public stream using server sent events and delivers
+// its events on the returned channel. Connection errors are delivered as
+// ErrorEvent and the stream reconnects until the context is done, which
+// closes the channel
+func (c *Client) StreamSSE(ctx context.Context, stream Stream) (<-chan Event, error) {
+	if err := stream.validate(); err != nil {
+		return nil, err
+	}
+
+	base, err := c.streamingURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	if stream.Tag != "" {
+		v.Set("tag", strings.TrimPrefix(stream.Tag, "#"))
+	}
+	endpoint := fmt.Sprintf("%s%s/%s", base, StreamingURI, strings.ReplaceAll(stream.Name, ":", "/"))
+	if len(v) > 0 {
+		endpoint += "?" + v.Encode()
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		hc := c.streamingClient()
+		delay := StreamReconnectDelay
+		for {
+			connected, err := c.readSSE(ctx, hc, endpoint, stream.names(), events)
+			if ctx.Err() != nil {
+				return
+			}
+			if connected {
+				delay = StreamReconnectDelay
+			}
+			if err == nil {
+				err = errors.New("stream closed by the server")
+			}
+
+			select {
+			case events <- &ErrorEvent{Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			delay, err = reconnectDelay(ctx, delay)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Read server sent events until the connection fails. Reports whether the
+// connection was established
+func (c *Client) readSSE(ctx context.Context, hc *http.Client, endpoint string, stream []string, events chan<- Event) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "text/event-stream")
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return false, newAPIError(endpoint, resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxStreamEventSize)
+
+	var name string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Blank line dispatches the event
+		if line == "" {
+			if name != "" && len(data) > 0 {
+				event, err := decodeEvent(name, strings.Join(data, "\n"), stream)
+				if err != nil {
+					event = &ErrorEvent{Err: fmt.Errorf("decoding %s event: %w", name, err)}
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return true, ctx.Err()
+				}
+			}
+			name, data = "", nil
+			continue
+		}
+
+		// Comments are used as heartbeats
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	return true, scanner.Err()
+}
+
+// WithStreamingURL sets the base URL of the streaming server instead of
+// discovering it from the instance configuration. Both http and websocket
+// schemes are accepted
+func WithStreamingURL(streamingurl string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(streamingurl)
+		if err != nil {
+			return err
+		}
+
+		switch u.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			return fmt.Errorf("invalid streaming url provided: %s", streamingurl)
+		}
+		c.streaming = strings.TrimSuffix(streamingurl, "/")
+
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamSSE(t *testing.T) {
+	// Setup Status
+	var statuses Statuses
+	err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+	if err != nil {
+		t.Fatalf("error unmarshalling test statuses: %v", err)
+	}
+	status, err := json.Marshal(statuses[0])
+	if err != nil {
+		t.Fatalf("error marshalling test status: %v", err)
+	}
+
+	connections := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return based on URI
+		switch r.URL.Path {
+		case InstanceURI:
+			streaming := strings.Replace(ts.URL, "http://", "ws://", 1)
+			fmt.Fprintf(w, `{"configuration": {"urls": {"streaming": "%s"}}}`+"\n", streaming)
+			return
+		case StreamingURI + "/hashtag/local":
+			if r.URL.Query().Get("tag") != "mastodon" || r.Header.Get("Accept") != "text/event-stream" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			connections++
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, ":)\n\n")
+			fmt.Fprintf(w, "event: update\ndata: %s\n\n", status)
+			fmt.Fprint(w, ":thump\n")
+			fmt.Fprintf(w, "event: status.update\ndata: %s\n\n", status)
+			fmt.Fprint(w, "event: delete\ndata: 108910940413327534\n\n")
+			fmt.Fprint(w, "event: announcement.delete\ndata: 7\n\n")
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.StreamSSE(context.Background(), Stream{Name: StreamHashtag})
+	if err == nil {
+		t.Fatalf("should fail for a hashtag stream without a tag")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := client.StreamSSE(ctx, Stream{Name: StreamHashtagLocal, Tag: "#mastodon"})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	names := []string{}
+	for event := range events {
+		names = append(names, event.EventName())
+
+		switch e := event.(type) {
+		case *UpdateEvent:
+			if e.Status.ID != "108910940413327534" || e.Stream[1] != "mastodon" {
+				t.Fatalf("update was incorrectly decoded: %+v", e)
+			}
+		case *DeleteEvent:
+			if e.ID != "108910940413327534" {
+				t.Fatalf("delete was incorrectly decoded: %+v", e)
+			}
+		case *UnknownEvent:
+			if e.Payload != "7" {
+				t.Fatalf("unknown event was incorrectly decoded: %+v", e)
+			}
+		}
+
+		// Stop after the events of the second connection
+		if len(names) == 10 {
+			cancel()
+		}
+	}
+
+	expected := "update,status.update,delete,announcement.delete,error"
+	if strings.Join(names, ",") != expected+","+expected {
+		t.Fatalf("received the wrong events: %v", names)
+	}
+
+	if connections != 2 {
+		t.Fatalf("should have reconnected once but connected: %d", connections)
+	}
+}
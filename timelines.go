@@ -0,0 +1,114 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	TimelinesPublicURI string = "/api/v1/timelines/public"
+	TimelinesTagURI    string = "/api/v1/timelines/tag/%s"
+)
+
+// Statuses hold a list of statuses
+type Statuses []Status
+
+// TimelineParams hold the filters for timeline requests
+type TimelineParams struct {
+	PageParams
+	Local     bool
+	Remote    bool
+	OnlyMedia bool
+}
+
+// TagTimelineParams hold the filters for hashtag timeline requests
+type TagTimelineParams struct {
+	TimelineParams
+	Any  []string
+	All  []string
+	None []string
+}
+
+// Add the hashtag timeline parameters to the query values
+func (p *TagTimelineParams) encode(v url.Values) {
+	if p == nil {
+		return
+	}
+	for _, tag := range p.Any {
+		v.Add("any[]", strings.TrimPrefix(tag, "#"))
+	}
+	for _, tag := range p.All {
+		v.Add("all[]", strings.TrimPrefix(tag, "#"))
+	}
+	for _, tag := range p.None {
+		v.Add("none[]", strings.TrimPrefix(tag, "#"))
+	}
+	p.TimelineParams.encode(v)
+}
+
+// Add the timeline parameters to the query values
+func (p *TimelineParams) encode(v url.Values) {
+	if p == nil {
+		return
+	}
+	if p.Local {
+		v.Set("local", "true")
+	}
+	if p.Remote {
+		v.Set("remote", "true")
+	}
+	if p.OnlyMedia {
+		v.Set("only_media", "true")
+	}
+	p.PageParams.encode(v)
+}
+
+// Get public statuses. The params are optional and may be nil
+func (c *Client) GetTimelinePublic(params *TimelineParams) (Statuses, error) {
+	return c.GetTimelinePublicContext(context.Background(), params)
+}
+
+// Same as GetTimelinePublic but the requests use the given context
+func (c *Client) GetTimelinePublicContext(ctx context.Context, params *TimelineParams) (Statuses, error) {
+	statuses := Statuses{}
+
+	v := url.Values{}
+	params.encode(v)
+	endpoint := c.buildURL(TimelinesPublicURI, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return statuses, err
+	}
+
+	err = json.Unmarshal(body, &statuses)
+
+	return statuses, err
+}
+
+// Get public statuses containing the given hashtag. The params are optional and may be nil
+func (c *Client) GetTimelineTag(hashtag string, params *TagTimelineParams) (Statuses, error) {
+	return c.GetTimelineTagContext(context.Background(), hashtag, params)
+}
+
+// Same as GetTimelineTag but the requests use the given context
+func (c *Client) GetTimelineTagContext(ctx context.Context, hashtag string, params *TagTimelineParams) (Statuses, error) {
+	statuses := Statuses{}
+
+	v := url.Values{}
+	params.encode(v)
+	uri := fmt.Sprintf(TimelinesTagURI, url.PathEscape(strings.TrimPrefix(hashtag, "#")))
+	endpoint := c.buildURL(uri, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return statuses, err
+	}
+
+	err = json.Unmarshal(body, &statuses)
+
+	return statuses, err
+}
@@ -0,0 +1,129 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetTimelinePublic(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup Statuses
+		var statuses Statuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case TimelinesPublicURI:
+			q := r.URL.Query()
+			if q.Get("local") != "true" || q.Get("only_media") != "true" || q.Get("remote") != "" {
+				t.Errorf("invalid filters: %s", r.URL.RawQuery)
+			}
+			if q.Get("max_id") != "200" || q.Get("limit") != "20" {
+				t.Errorf("invalid paging: %s", r.URL.RawQuery)
+			}
+
+			body, err := json.Marshal(statuses)
+			if err != nil {
+				t.Fatalf("error marshalling test statuses: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	params := &TimelineParams{
+		PageParams: PageParams{MaxID: "200", Limit: 20},
+		Local:      true,
+		OnlyMedia:  true,
+	}
+
+	statuses, err := client.GetTimelinePublic(params)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(statuses) != 1 {
+		t.Fatalf("should have returned 1 status but instead returned: %d", len(statuses))
+	}
+}
+
+func TestGetTimelineTag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup Statuses
+		var statuses Statuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case fmt.Sprintf(TimelinesTagURI, "mastodon"):
+			q := r.URL.Query()
+			if !reflect.DeepEqual(q["any[]"], []string{"fediverse", "activitypub"}) {
+				t.Errorf("invalid any[] tags: %v", q["any[]"])
+			}
+			if !reflect.DeepEqual(q["none[]"], []string{"twitter"}) {
+				t.Errorf("invalid none[] tags: %v", q["none[]"])
+			}
+			if _, ok := q["all[]"]; ok {
+				t.Errorf("all[] should not be set: %v", q["all[]"])
+			}
+			if q.Get("remote") != "true" || q.Get("since_id") != "100" {
+				t.Errorf("invalid filters: %s", r.URL.RawQuery)
+			}
+
+			body, err := json.Marshal(statuses)
+			if err != nil {
+				t.Fatalf("error marshalling test statuses: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	params := &TagTimelineParams{
+		TimelineParams: TimelineParams{
+			PageParams: PageParams{SinceID: "100"},
+			Remote:     true,
+		},
+		Any:  []string{"#fediverse", "activitypub"},
+		None: []string{"twitter"},
+	}
+
+	statuses, err := client.GetTimelineTag("#mastodon", params)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(statuses) != 1 {
+		t.Fatalf("should have returned 1 status but instead returned: %d", len(statuses))
+	}
+}
@@ -1,58 +1,87 @@
 package mastodon
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"net/url"
+	"strconv"
 )
 
 const (
 	TrendsLinksURI    string = "/api/v1/trends/links"
 	TrendsStatusesURI string = "/api/v1/trends/statuses"
 	TrendsTagsURI     string = "/api/v1/trends/tags"
+
+	// Maximum number of results Mastodon returns per page of trends
+	TrendsLinksPageLimit    int = 20
+	TrendsStatusesPageLimit int = 40
+	TrendsTagsPageLimit     int = 20
 )
 
-// TrendsLinks hold information on links
-type TrendLinks []struct {
-	URL          string `json:"url"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	Type         string `json:"type"`
-	AuthorName   string `json:"author_name"`
-	AuthorURL    string `json:"author_url"`
-	ProviderName string `json:"provider_name"`
-	ProviderURL  string `json:"provider_url"`
-	HTML         string `json:"html"`
-	Width        int    `json:"width"`
-	Height       int    `json:"height"`
-	Image        string `json:"image"`
-	EmbedURL     string `json:"embed_url"`
-	Blurhash     string `json:"blurhash"`
-	History      []struct {
-		Day      string `json:"day"`
-		Accounts string `json:"accounts"`
-		Uses     string `json:"uses"`
-	} `json:"history"`
+// TrendHistory hold daily usage information for a trend
+type TrendHistory struct {
+	Day      string `json:"day"`
+	Accounts string `json:"accounts"`
+	Uses     string `json:"uses"`
 }
 
-// TrendsTags hold information for tags
-type TrendTags []struct {
-	Name    string `json:"name"`
-	URL     string `json:"url"`
-	History []struct {
-		Day      string `json:"day"`
-		Accounts string `json:"accounts"`
-		Uses     string `json:"uses"`
-	} `json:"history"`
-	Following bool `json:"following"`
+// TrendsParams hold the offset based paging parameters for trends requests
+type TrendsParams struct {
+	Limit  int
+	Offset int
+}
+
+// Add the trends parameters to the query values
+func (p *TrendsParams) encode(v url.Values) {
+	if p == nil {
+		return
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		v.Set("offset", strconv.Itoa(p.Offset))
+	}
 }
 
+// TrendsLinks hold information on links
+type TrendLinks []PreviewCard
+
+// TrendStatuses hold information on statuses
+type TrendStatuses []Status
+
+// TrendsTags hold information for tags
+type TrendTags []Tag
+
 // Get links that have been shared more than others
 func (c *Client) GetTrendsLinks() (TrendLinks, error) {
+	return c.GetTrendsLinksContext(context.Background())
+}
+
+// Same as GetTrendsLinks but the requests use the given context
+func (c *Client) GetTrendsLinksContext(ctx context.Context) (TrendLinks, error) {
+	return c.GetTrendsLinksPageContext(ctx, nil)
+}
+
+// Get a page of links using the limit and offset of the params. The params are optional and may be nil
+func (c *Client) GetTrendsLinksPage(params *TrendsParams) (TrendLinks, error) {
+	return c.GetTrendsLinksPageContext(context.Background(), params)
+}
+
+// Same as GetTrendsLinksPage but the requests use the given context
+func (c *Client) GetTrendsLinksPageContext(ctx context.Context, params *TrendsParams) (TrendLinks, error) {
 	links := TrendLinks{}
 
-	url := fmt.Sprintf("%s%s", c.Server, TrendsLinksURI)
+	if err := c.require(FeatureTrendsLinks); err != nil {
+		return links, err
+	}
+
+	v := url.Values{}
+	params.encode(v)
+	endpoint := c.buildURL(TrendsLinksURI, v)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, endpoint)
 	if err != nil {
 		return links, err
 	}
@@ -62,29 +91,99 @@ func (c *Client) GetTrendsLinks() (TrendLinks, error) {
 	return links, err
 }
 
+// Get every trending link by requesting pages until the server returns an empty page
+func (c *Client) GetAllTrendsLinks() (TrendLinks, error) {
+	return c.GetAllTrendsLinksContext(context.Background())
+}
+
+// Same as GetAllTrendsLinks but the requests use the given context
+func (c *Client) GetAllTrendsLinksContext(ctx context.Context) (TrendLinks, error) {
+	if err := c.require(FeatureTrendsLinks); err != nil {
+		return TrendLinks{}, err
+	}
+
+	return walkTrends[TrendLinks](ctx, c, TrendsLinksURI, TrendsLinksPageLimit)
+}
+
 // Get statuses that have been interacted with more than others
-// func (c *Client) GetTrendsStatuses() (TrendStatuses, error) {
-// 	statuses := TrendStatuses{}
+func (c *Client) GetTrendsStatuses() (TrendStatuses, error) {
+	return c.GetTrendsStatusesContext(context.Background())
+}
 
-// 	url := fmt.Sprintf("https://%s%s", c.Server, TrendsStatusesURI)
+// Same as GetTrendsStatuses but the requests use the given context
+func (c *Client) GetTrendsStatusesContext(ctx context.Context) (TrendStatuses, error) {
+	return c.GetTrendsStatusesPageContext(ctx, nil)
+}
 
-// 	body, err := c.SendRequest(url)
-// 	if err != nil {
-// 		return statuses, err
-// 	}
+// Get a page of statuses using the limit and offset of the params. The params are optional and may be nil
+func (c *Client) GetTrendsStatusesPage(params *TrendsParams) (TrendStatuses, error) {
+	return c.GetTrendsStatusesPageContext(context.Background(), params)
+}
 
-// 	err = json.Unmarshal(body, &statuses)
+// Same as GetTrendsStatusesPage but the requests use the given context
+func (c *Client) GetTrendsStatusesPageContext(ctx context.Context, params *TrendsParams) (TrendStatuses, error) {
+	statuses := TrendStatuses{}
 
-// 	return statuses, err
-// }
+	if err := c.require(FeatureTrendsStatuses); err != nil {
+		return statuses, err
+	}
+
+	v := url.Values{}
+	params.encode(v)
+	endpoint := c.buildURL(TrendsStatusesURI, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return statuses, err
+	}
+
+	err = json.Unmarshal(body, &statuses)
+
+	return statuses, err
+}
+
+// Get every trending status by requesting pages until the server returns an empty page
+func (c *Client) GetAllTrendsStatuses() (TrendStatuses, error) {
+	return c.GetAllTrendsStatusesContext(context.Background())
+}
+
+// Same as GetAllTrendsStatuses but the requests use the given context
+func (c *Client) GetAllTrendsStatusesContext(ctx context.Context) (TrendStatuses, error) {
+	if err := c.require(FeatureTrendsStatuses); err != nil {
+		return TrendStatuses{}, err
+	}
+
+	return walkTrends[TrendStatuses](ctx, c, TrendsStatusesURI, TrendsStatusesPageLimit)
+}
 
 // Get tags that are being used more frequently within the past week
 func (c *Client) GetTrendsTags() (TrendTags, error) {
+	return c.GetTrendsTagsContext(context.Background())
+}
+
+// Same as GetTrendsTags but the requests use the given context
+func (c *Client) GetTrendsTagsContext(ctx context.Context) (TrendTags, error) {
+	return c.GetTrendsTagsPageContext(ctx, nil)
+}
+
+// Get a page of tags using the limit and offset of the params. The params are optional and may be nil
+func (c *Client) GetTrendsTagsPage(params *TrendsParams) (TrendTags, error) {
+	return c.GetTrendsTagsPageContext(context.Background(), params)
+}
+
+// Same as GetTrendsTagsPage but the requests use the given context
+func (c *Client) GetTrendsTagsPageContext(ctx context.Context, params *TrendsParams) (TrendTags, error) {
 	tags := TrendTags{}
 
-	url := fmt.Sprintf("%s%s", c.Server, TrendsTagsURI)
+	if err := c.require(FeatureTrendsTags); err != nil {
+		return tags, err
+	}
+
+	v := url.Values{}
+	params.encode(v)
+	endpoint := c.buildURL(TrendsTagsURI, v)
 
-	body, err := c.SendRequest(url)
+	body, err := c.SendRequestContext(ctx, endpoint)
 	if err != nil {
 		return tags, err
 	}
@@ -93,3 +192,54 @@ func (c *Client) GetTrendsTags() (TrendTags, error) {
 
 	return tags, err
 }
+
+// Get every trending tag by requesting pages until the server returns an empty page
+func (c *Client) GetAllTrendsTags() (TrendTags, error) {
+	return c.GetAllTrendsTagsContext(context.Background())
+}
+
+// Same as GetAllTrendsTags but the requests use the given context
+func (c *Client) GetAllTrendsTagsContext(ctx context.Context) (TrendTags, error) {
+	if err := c.require(FeatureTrendsTags); err != nil {
+		return TrendTags{}, err
+	}
+
+	return walkTrends[TrendTags](ctx, c, TrendsTagsURI, TrendsTagsPageLimit)
+}
+
+// Request pages of trends until the server returns an empty page
+func walkTrends[T ~[]E, E any](ctx context.Context, c *Client, uri string, limit int) (T, error) {
+	all := T{}
+	params := TrendsParams{Limit: limit}
+	var previous []byte
+
+	for {
+		v := url.Values{}
+		params.encode(v)
+		endpoint := c.buildURL(uri, v)
+
+		body, err := c.SendRequestContext(ctx, endpoint)
+		if err != nil {
+			return all, err
+		}
+
+		// Servers that do not support the offset return the same page again
+		if bytes.Equal(body, previous) {
+			return all, nil
+		}
+		previous = body
+
+		var page T
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return all, err
+		}
+
+		if len(page) == 0 {
+			return all, nil
+		}
+
+		all = append(all, page...)
+		params.Offset += len(page)
+	}
+}
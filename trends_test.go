@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 )
 
@@ -100,6 +101,114 @@ const (
 		  ]
 		}
 	  ]`
+
+	testtrendsstatuses string = `[
+		{
+		  "id": "108910940413327534",
+		  "created_at": "2022-08-30T08:44:26.366Z",
+		  "in_reply_to_id": null,
+		  "in_reply_to_account_id": null,
+		  "sensitive": false,
+		  "spoiler_text": "",
+		  "visibility": "public",
+		  "language": "en",
+		  "uri": "https://mastodon.social/users/Mastodon/statuses/108910940413327534",
+		  "url": "https://mastodon.social/@Mastodon/108910940413327534",
+		  "replies_count": 8,
+		  "reblogs_count": 325,
+		  "favourites_count": 668,
+		  "edited_at": "2022-08-30T09:01:12.000Z",
+		  "content": "<p>Mastodon 3.5.3 is a security release, please update!</p>",
+		  "reblog": null,
+		  "application": {
+			"name": "Web",
+			"website": null
+		  },
+		  "account": {
+			"id": "13179",
+			"username": "Mastodon",
+			"acct": "Mastodon",
+			"display_name": "Mastodon",
+			"locked": false,
+			"bot": false,
+			"discoverable": true,
+			"group": false,
+			"created_at": "2016-11-23T00:00:00.000Z",
+			"note": "<p>Free, open-source decentralized social media platform.</p>",
+			"url": "https://mastodon.social/@Mastodon",
+			"avatar": "https://files.mastodon.social/accounts/avatars/000/013/179/original/b4ceb19c9c54ec7e.png",
+			"avatar_static": "https://files.mastodon.social/accounts/avatars/000/013/179/original/b4ceb19c9c54ec7e.png",
+			"header": "https://files.mastodon.social/accounts/headers/000/013/179/original/1375be116fbe0f1d.png",
+			"header_static": "https://files.mastodon.social/accounts/headers/000/013/179/original/1375be116fbe0f1d.png",
+			"followers_count": 811304,
+			"following_count": 34,
+			"statuses_count": 239,
+			"last_status_at": "2022-08-29",
+			"emojis": [],
+			"fields": []
+		  },
+		  "media_attachments": [
+			{
+			  "id": "108910940312352843",
+			  "type": "image",
+			  "url": "https://files.mastodon.social/media_attachments/files/108/910/940/312/352/843/original/0a8b6a5ab7ba8b5b.png",
+			  "preview_url": "https://files.mastodon.social/media_attachments/files/108/910/940/312/352/843/small/0a8b6a5ab7ba8b5b.png",
+			  "remote_url": null,
+			  "meta": {
+				"original": {
+				  "width": 1200,
+				  "height": 630,
+				  "size": "1200x630",
+				  "aspect": 1.9047619047619047
+				},
+				"small": {
+				  "width": 640,
+				  "height": 336,
+				  "size": "640x336",
+				  "aspect": 1.9047619047619047
+				}
+			  },
+			  "description": "Mastodon logo",
+			  "blurhash": "UBL;*Zof00WBWBj[fQfQ00ay~qj[ayfQfQfQ"
+			}
+		  ],
+		  "mentions": [
+			{
+			  "id": "1",
+			  "username": "Gargron",
+			  "url": "https://mastodon.social/@Gargron",
+			  "acct": "Gargron"
+			}
+		  ],
+		  "tags": [
+			{
+			  "name": "mastodon",
+			  "url": "https://mastodon.social/tags/mastodon"
+			}
+		  ],
+		  "emojis": [],
+		  "card": null,
+		  "poll": {
+			"id": "34830",
+			"expires_at": "2022-09-06T08:44:26.366Z",
+			"expired": false,
+			"multiple": false,
+			"votes_count": 10,
+			"voters_count": 10,
+			"options": [
+			  {
+				"title": "Updated",
+				"votes_count": 7
+			  },
+			  {
+				"title": "Not yet",
+				"votes_count": 3
+			  }
+			],
+			"emojis": []
+		  }
+		}
+	  ]`
 )
 
 func TestGetTrendsLinks(t *testing.T) {
@@ -139,6 +248,59 @@ func TestGetTrendsLinks(t *testing.T) {
 	}
 }
 
+func TestGetTrendsStatuses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setup TrendStatuses
+		var trendsstatuses TrendStatuses
+		err := json.Unmarshal([]byte(testtrendsstatuses), &trendsstatuses)
+		if err != nil {
+			t.Fatalf("error unmarshalling test trends statuses: %v", err)
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case TrendsStatusesURI:
+			body, err := json.Marshal(trendsstatuses)
+			if err != nil {
+				t.Fatalf("error marshalling trends statuses: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	statuses, err := client.GetTrendsStatuses()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if len(statuses) != 1 {
+		t.Fatalf("should have returned 1 status but instead returned: %d", len(statuses))
+	}
+
+	if statuses[0].Account.Acct != "Mastodon" {
+		t.Fatalf("account was incorrectly set to: %s", statuses[0].Account.Acct)
+	}
+
+	if statuses[0].Poll == nil || len(statuses[0].Poll.Options) != 2 {
+		t.Fatalf("poll was not parsed correctly: %+v", statuses[0].Poll)
+	}
+
+	if statuses[0].EditedAt == nil {
+		t.Fatalf("edited_at was not parsed")
+	}
+}
+
 func TestGetTrendsTags(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Setup TrendsTags
@@ -175,3 +337,75 @@ func TestGetTrendsTags(t *testing.T) {
 		t.Fatalf("should not be fail: %v", err)
 	}
 }
+
+func TestGetAllTrendsTags(t *testing.T) {
+	// Setup 45 trending tags
+	tags := TrendTags{}
+	for i := 0; i < 45; i++ {
+		tags = append(tags, Tag{Name: fmt.Sprintf("tag%d", i)})
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit == 0 {
+			limit = 10
+		}
+
+		// Return based on URI
+		switch r.URL.Path {
+		case TrendsTagsURI:
+			start, end := offset, offset+limit
+			if start > len(tags) {
+				start = len(tags)
+			}
+			if end > len(tags) {
+				end = len(tags)
+			}
+			body, err := json.Marshal(tags[start:end])
+			if err != nil {
+				t.Fatalf("error marshalling trends tags: %v", err)
+			}
+			fmt.Fprintln(w, string(body))
+			return
+		case TrendsLinksURI:
+			// Server without offset support
+			fmt.Fprintln(w, testtrendslinks)
+			return
+		}
+
+		// URI not specified above, return status not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	page, err := client.GetTrendsTagsPage(&TrendsParams{Limit: 5, Offset: 40})
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(page) != 5 || page[0].Name != "tag40" {
+		t.Fatalf("should have returned tags 40 to 44 instead got: %v", page)
+	}
+
+	all, err := client.GetAllTrendsTags()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(all) != 45 || all[44].Name != "tag44" {
+		t.Fatalf("should have returned 45 tags but instead returned: %d", len(all))
+	}
+
+	links, err := client.GetAllTrendsLinks()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("should stop when the offset is ignored but returned: %d", len(links))
+	}
+}
@@ -0,0 +1,241 @@
+package mastodon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is an API feature that is only available from a Mastodon version
+type Feature string
+
+// Features of the public API that were added after Mastodon 2.0
+const (
+	FeatureInstanceV2           Feature = "instance v2"
+	FeatureInstancePeers        Feature = "instance peers"
+	FeatureInstanceActivity     Feature = "instance activity"
+	FeatureInstanceRules        Feature = "instance rules"
+	FeatureInstanceDomainBlocks Feature = "instance domain blocks"
+	FeatureAccountLookup        Feature = "account lookup"
+	FeatureDirectory            Feature = "profile directory"
+	FeaturePolls                Feature = "polls"
+	FeatureTrendsTags           Feature = "trending tags"
+	FeatureTrendsStatuses       Feature = "trending statuses"
+	FeatureTrendsLinks          Feature = "trending links"
+	FeatureStreamingMultiplex   Feature = "multiplexed streaming"
+)
+
+// Mastodon version that added each feature
+var featureVersions = map[Feature]ServerVersion{
+	FeatureInstanceV2:           {Major: 4},
+	FeatureInstancePeers:        {Major: 2, Minor: 1, Patch: 2},
+	FeatureInstanceActivity:     {Major: 2, Minor: 1, Patch: 2},
+	FeatureInstanceRules:        {Major: 3, Minor: 4},
+	FeatureInstanceDomainBlocks: {Major: 4},
+	FeatureAccountLookup:        {Major: 3, Minor: 4},
+	FeatureDirectory:            {Major: 3},
+	FeaturePolls:                {Major: 2, Minor: 8},
+	FeatureTrendsTags:           {Major: 3, Minor: 5},
+	FeatureTrendsStatuses:       {Major: 3, Minor: 5},
+	FeatureTrendsLinks:          {Major: 3, Minor: 5},
+	FeatureStreamingMultiplex:   {Major: 3, Minor: 3},
+}
+
+// ServerVersion hold the parts of the version reported by a server, such as
+// "4.0.2+glitch" or "2.7.2 (compatible; Pleroma 2.5.0)"
+type ServerVersion struct {
+	Raw        string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	// Build suffix used by Mastodon forks, such as glitch or hometown-1.1.1
+	Fork string
+	// Software that only claims compatibility with the Mastodon version, such as Pleroma 2.5.0
+	Compatible string
+}
+
+// Parse the version string reported by a server. The minor and patch numbers
+// are optional and the prerelease may follow the patch with or without a dash
+func ParseVersion(version string) (ServerVersion, error) {
+	v := ServerVersion{Raw: version}
+
+	s := strings.TrimSpace(version)
+	if i := strings.Index(s, "("); i >= 0 {
+		compatible := strings.TrimSuffix(strings.TrimSpace(s[i+1:]), ")")
+		compatible = strings.TrimSpace(strings.TrimPrefix(compatible, "compatible;"))
+		v.Compatible = compatible
+		s = strings.TrimSpace(s[:i])
+	}
+
+	if i := strings.Index(s, "+"); i >= 0 {
+		v.Fork = s[i+1:]
+		s = s[:i]
+	}
+
+	// Split the dotted numbers from the prerelease
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if end >= 0 {
+		v.Prerelease = strings.TrimPrefix(strings.TrimSpace(s[end:]), "-")
+		s = s[:end]
+	}
+
+	parts := strings.Split(strings.TrimSuffix(s, "."), ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid server version: %s", version)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return v, fmt.Errorf("invalid server version: %s", version)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// String returns the upstream version without the prerelease, fork or compatibility string
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether the upstream version is the given version or newer.
+// The prerelease is ignored, so release candidates count as the release
+func (v ServerVersion) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+
+	return v.Patch >= patch
+}
+
+// Supports reports whether the upstream version has the feature. Features
+// that are not in the capability matrix are always supported
+func (v ServerVersion) Supports(feature Feature) bool {
+	min, ok := featureVersions[feature]
+	if !ok {
+		return true
+	}
+
+	return v.AtLeast(min.Major, min.Minor, min.Patch)
+}
+
+// Report whether the version has the form used by Mastodon and its forks.
+// Other software such as GoToSocial reports its own version, for example
+// "0.16.0 git-abcdef", or adds a compatibility string
+func mastodonVersion(v ServerVersion) bool {
+	return v.Compatible == "" && v.Major > 0 && !strings.ContainsAny(strings.TrimSpace(v.Raw), " \t")
+}
+
+// Capabilities returns whether each feature of the capability matrix is supported
+func (v ServerVersion) Capabilities() map[Feature]bool {
+	capabilities := make(map[Feature]bool, len(featureVersions))
+	for feature := range featureVersions {
+		capabilities[feature] = v.Supports(feature)
+	}
+
+	return capabilities
+}
+
+// Track the version of the server across requests
+type versionTracker struct {
+	mu       sync.Mutex
+	version  ServerVersion
+	known    bool
+	mastodon bool
+}
+
+// Get the version of the server if it is known
+func (t *versionTracker) get() (ServerVersion, bool) {
+	if t == nil {
+		return ServerVersion{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.version, t.known
+}
+
+// Report whether the server is known to run Mastodon or one of its forks
+func (t *versionTracker) isMastodon() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.known && t.mastodon
+}
+
+// Set the version of the server and whether it runs Mastodon
+func (t *versionTracker) set(version ServerVersion, mastodon bool) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.version = version
+	t.known = true
+	t.mastodon = mastodon
+}
+
+// ServerVersion returns the version of the server, which is known once
+// GetInstanceData has succeeded or when set with WithServerVersion
+func (c *Client) ServerVersion() (ServerVersion, bool) {
+	return c.version.get()
+}
+
+// Record the version reported by the server. Versions that can not be
+// parsed are ignored so they never block requests. The server is taken to run
+// Mastodon when NodeInfo says so or when the version has the Mastodon form
+func (c *Client) setServerVersion(version string, mastodon bool) {
+	if v, err := ParseVersion(version); err == nil {
+		c.version.set(v, mastodon || mastodonVersion(v))
+	}
+}
+
+// Return an UnsupportedError if the server is known to lack the feature.
+// Only servers known to run Mastodon or one of its forks are checked, since
+// other software reports its own versions or claims compatibility with old
+// Mastodon versions while adding newer features
+func (c *Client) require(feature Feature) error {
+	v, ok := c.ServerVersion()
+	if !ok || !c.version.isMastodon() || v.Supports(feature) {
+		return nil
+	}
+
+	return &UnsupportedError{
+		Feature:  feature,
+		Version:  v,
+		Required: featureVersions[feature],
+	}
+}
+
+// WithServerVersion sets the version of the server, such as "4.0.2+glitch",
+// so that unsupported features fail without requesting the instance first.
+// Versions of other software, such as "0.16.0 git-abcdef", are recorded but
+// never make requests fail
+func WithServerVersion(version string) Option {
+	return func(c *Client) error {
+		v, err := ParseVersion(version)
+		if err != nil {
+			return err
+		}
+		c.version.set(v, mastodonVersion(v))
+
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package mastodon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    ServerVersion
+		fail    bool
+	}{
+		{version: "4.1.2", want: ServerVersion{Major: 4, Minor: 1, Patch: 2}},
+		{version: "4.0.2+glitch", want: ServerVersion{Major: 4, Minor: 0, Patch: 2, Fork: "glitch"}},
+		{version: "4.1.0+hometown-1.1.1", want: ServerVersion{Major: 4, Minor: 1, Patch: 0, Fork: "hometown-1.1.1"}},
+		{version: "4.0.0rc1", want: ServerVersion{Major: 4, Prerelease: "rc1"}},
+		{version: "4.2.0-beta2", want: ServerVersion{Major: 4, Minor: 2, Prerelease: "beta2"}},
+		{version: "3.5", want: ServerVersion{Major: 3, Minor: 5}},
+		{version: "2.7.2 (compatible; Pleroma 2.5.0)", want: ServerVersion{Major: 2, Minor: 7, Patch: 2, Compatible: "Pleroma 2.5.0"}},
+		{version: "3.5.3+git-3b5b7ab (compatible; Akkoma 3.10.4)", want: ServerVersion{Major: 3, Minor: 5, Patch: 3, Fork: "git-3b5b7ab", Compatible: "Akkoma 3.10.4"}},
+		{version: "0.16.0 git-abcdef", want: ServerVersion{Major: 0, Minor: 16, Patch: 0, Prerelease: "git-abcdef"}},
+		{version: "", fail: true},
+		{version: "unknown", fail: true},
+		{version: "1.2.3.4", fail: true},
+	}
+
+	for _, test := range tests {
+		v, err := ParseVersion(test.version)
+		if test.fail {
+			if err == nil {
+				t.Fatalf("%q should fail", test.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q should not be fail: %v", test.version, err)
+		}
+
+		test.want.Raw = test.version
+		if v != test.want {
+			t.Fatalf("%q: got %+v, want %+v", test.version, v, test.want)
+		}
+	}
+}
+
+func TestServerVersionSupports(t *testing.T) {
+	v, err := ParseVersion("3.5.3")
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if !v.Supports(FeatureTrendsStatuses) || !v.Supports(FeatureInstanceRules) {
+		t.Fatalf("3.5.3 should support trending statuses and rules")
+	}
+	if v.Supports(FeatureInstanceV2) || v.Supports(FeatureInstanceDomainBlocks) {
+		t.Fatalf("3.5.3 should not support instance v2 or domain blocks")
+	}
+	if !v.Supports(Feature("unknown")) {
+		t.Fatalf("features outside the matrix should be supported")
+	}
+
+	capabilities := v.Capabilities()
+	if len(capabilities) != len(featureVersions) {
+		t.Fatalf("expected %d capabilities, got %d", len(featureVersions), len(capabilities))
+	}
+	if capabilities[FeatureInstanceV2] {
+		t.Fatalf("3.5.3 should not report instance v2")
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprintln(w, "[]")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, WithServerVersion("3.4.1+glitch"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.GetTrendsStatuses()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("should be unsupported: %v", err)
+	}
+
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Feature != FeatureTrendsStatuses {
+		t.Fatalf("should be an UnsupportedError for trending statuses: %v", err)
+	}
+
+	_, err = client.GetInstanceDomainsBlocked()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("should be unsupported: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("unsupported features should not send requests, got %d", n)
+	}
+
+	// Supported features are requested
+	_, err = client.GetInstanceRules()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	// Servers claiming compatibility are not checked
+	compatible, err := NewClient(ts.URL, WithServerVersion("2.7.2 (compatible; Pleroma 2.5.0)"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, err = compatible.GetDirectory(nil)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if _, err := NewClient(ts.URL, WithServerVersion("unknown")); err == nil {
+		t.Fatalf("invalid version should fail")
+	}
+}
+
+func TestGetInstanceDataServerVersion(t *testing.T) {
+	var v2requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case InstanceURI:
+			atomic.AddInt32(&v2requests, 1)
+			fmt.Fprintln(w, testinstance)
+			return
+		case InstanceV1URI:
+			fmt.Fprintln(w, `{"uri": "old.example", "version": "3.5.3"}`)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.GetInstanceData(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	v, ok := client.ServerVersion()
+	if !ok || v.Major != 4 || v.Prerelease != "rc1" {
+		t.Fatalf("server version should be recorded, got %+v", v)
+	}
+
+	// Servers known to be older than 4.0 are asked for v1 directly
+	old, err := NewClient(ts.URL, WithServerVersion("3.5.3"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	instance, err := old.GetInstanceData()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if instance.Domain != "old.example" {
+		t.Fatalf("instance should come from v1, got %s", instance.Domain)
+	}
+	if n := atomic.LoadInt32(&v2requests); n != 1 {
+		t.Fatalf("v2 should be requested once, got %d", n)
+	}
+}
+
+func TestRequireFeatureOtherSoftware(t *testing.T) {
+	var peersrequests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case InstanceV1URI:
+			fmt.Fprintln(w, `{"uri": "gts.example", "version": "0.16.0 git-abcdef"}`)
+			return
+		case InstancePeersURI:
+			atomic.AddInt32(&peersrequests, 1)
+			fmt.Fprintln(w, `["mastodon.social"]`)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.GetInstanceData(); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	v, ok := client.ServerVersion()
+	if !ok || v.Major != 0 || v.Minor != 16 || v.Prerelease != "git-abcdef" {
+		t.Fatalf("server version should be recorded, got %+v", v)
+	}
+
+	// GoToSocial reports its own version, which must not block requests
+	peers, err := client.GetInstancePeers()
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+	if len(peers) != 1 || atomic.LoadInt32(&peersrequests) != 1 {
+		t.Fatalf("peers should be requested, got %v", peers)
+	}
+}
@@ -0,0 +1,151 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	WebFingerURI string = "/.well-known/webfinger"
+
+	// Link relations of the webfinger document
+	WebFingerRelSelf        string = "self"
+	WebFingerRelProfilePage string = "http://webfinger.net/rel/profile-page"
+)
+
+// WebFinger hold the links of the webfinger document of an account
+type WebFinger struct {
+	Subject string   `json:"subject"`
+	Aliases []string `json:"aliases"`
+	Links   []Link   `json:"links"`
+}
+
+// ActorURL returns the url of the ActivityPub actor of the account
+func (w WebFinger) ActorURL() string {
+	for _, link := range w.Links {
+		if link.Rel != WebFingerRelSelf {
+			continue
+		}
+		if link.Type == "application/activity+json" || strings.HasPrefix(link.Type, "application/ld+json") {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
+// ProfileURL returns the url of the profile page of the account
+func (w WebFinger) ProfileURL() string {
+	for _, link := range w.Links {
+		if link.Rel == WebFingerRelProfilePage {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
+// ResolvedHandle hold the account of a handle and a client for the server hosting it
+type ResolvedHandle struct {
+	Username   string
+	Domain     string
+	ActorURL   string
+	ProfileURL string
+	Client     *Client
+	Account    Account
+}
+
+// Split a handle such as @user@domain, user@domain or acct:user@domain into
+// the username and domain
+func ParseHandle(handle string) (string, string, error) {
+	acct := strings.TrimPrefix(strings.TrimSpace(handle), "acct:")
+	acct = strings.TrimPrefix(acct, "@")
+
+	username, domain, ok := strings.Cut(acct, "@")
+	if !ok || username == "" || domain == "" || strings.ContainsAny(username, ":/") || strings.ContainsAny(domain, "@/") {
+		return "", "", fmt.Errorf("invalid handle: %s", handle)
+	}
+
+	return username, strings.ToLower(domain), nil
+}
+
+// Get the webfinger document for the resource, such as acct:user@domain
+func (c *Client) GetWebFinger(resource string) (WebFinger, error) {
+	return c.GetWebFingerContext(context.Background(), resource)
+}
+
+// Same as GetWebFinger but the requests use the given context
+func (c *Client) GetWebFingerContext(ctx context.Context, resource string) (WebFinger, error) {
+	webfinger := WebFinger{}
+
+	v := url.Values{}
+	v.Set("resource", resource)
+	endpoint := c.buildURL(WebFingerURI, v)
+
+	body, err := c.SendRequestContext(ctx, endpoint)
+	if err != nil {
+		return webfinger, err
+	}
+
+	err = json.Unmarshal(body, &webfinger)
+
+	return webfinger, err
+}
+
+// Resolve a handle such as @user@domain to its account. The webfinger
+// document is requested from the domain of the handle, and the account is
+// looked up on the server hosting the ActivityPub actor, which differs from
+// the domain of the handle when the server uses a separate web domain. The
+// clients are created with the options
+func ResolveHandle(handle string, opts ...Option) (ResolvedHandle, error) {
+	return ResolveHandleContext(context.Background(), handle, opts...)
+}
+
+// Same as ResolveHandle but the requests use the given context
+func ResolveHandleContext(ctx context.Context, handle string, opts ...Option) (ResolvedHandle, error) {
+	resolved := ResolvedHandle{}
+
+	username, domain, err := ParseHandle(handle)
+	if err != nil {
+		return resolved, err
+	}
+	resolved.Username = username
+	resolved.Domain = domain
+
+	c, err := NewClient("https://"+domain, opts...)
+	if err != nil {
+		return resolved, err
+	}
+
+	webfinger, err := c.GetWebFingerContext(ctx, fmt.Sprintf("acct:%s@%s", username, domain))
+	if err != nil {
+		return resolved, err
+	}
+	resolved.ActorURL = webfinger.ActorURL()
+	resolved.ProfileURL = webfinger.ProfileURL()
+
+	// The subject holds the canonical username when the handle used an alias
+	if subjectuser, _, err := ParseHandle(webfinger.Subject); err == nil {
+		resolved.Username = subjectuser
+	}
+
+	if actor, err := url.Parse(resolved.ActorURL); err == nil && actor.Host != "" && !strings.EqualFold(actor.Host, domain) {
+		c, err = NewClient("https://"+actor.Host, opts...)
+		if err != nil {
+			return resolved, err
+		}
+	}
+	resolved.Client = c
+
+	// The account is local to the server hosting the actor
+	account, err := c.LookupAccountContext(ctx, resolved.Username)
+	if err != nil {
+		return resolved, err
+	}
+	resolved.Account = account
+
+	return resolved, nil
+}
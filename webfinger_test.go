@@ -0,0 +1,105 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseHandle(t *testing.T) {
+	tests := []struct {
+		handle   string
+		username string
+		domain   string
+		fail     bool
+	}{
+		{handle: "@Gargron@mastodon.social", username: "Gargron", domain: "mastodon.social"},
+		{handle: "Gargron@Mastodon.Social", username: "Gargron", domain: "mastodon.social"},
+		{handle: "acct:gargron@mastodon.social", username: "gargron", domain: "mastodon.social"},
+		{handle: "@Gargron", fail: true},
+		{handle: "@Gargron@", fail: true},
+		{handle: "a@b@c", fail: true},
+		{handle: "https://mastodon.social/@Gargron", fail: true},
+	}
+
+	for _, test := range tests {
+		username, domain, err := ParseHandle(test.handle)
+		if test.fail {
+			if err == nil {
+				t.Fatalf("%q should fail", test.handle)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q should not be fail: %v", test.handle, err)
+		}
+		if username != test.username || domain != test.domain {
+			t.Fatalf("%q: got %s %s", test.handle, username, domain)
+		}
+	}
+}
+
+func TestResolveHandle(t *testing.T) {
+	// The handle uses example.com while the server runs on social.example.com
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "example.com" && r.URL.Path == WebFingerURI:
+			if r.URL.Query().Get("resource") != "acct:gargron@example.com" {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintln(w, `{
+				"subject": "acct:Gargron@example.com",
+				"aliases": ["https://social.example.com/@Gargron"],
+				"links": [
+				  {"rel": "http://webfinger.net/rel/profile-page", "type": "text/html", "href": "https://social.example.com/@Gargron"},
+				  {"rel": "self", "type": "application/activity+json", "href": "https://social.example.com/users/Gargron"}
+				]
+			}`)
+			return
+		case r.Host == "social.example.com" && r.URL.Path == AccountsLookupURI:
+			if r.URL.Query().Get("acct") != "Gargron" {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			fmt.Fprintln(w, testaccount)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	target, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server url: %v", err)
+	}
+
+	resolved, err := ResolveHandle("@gargron@example.com", WithTransport(&rewriteTransport{target: target}))
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if resolved.ActorURL != "https://social.example.com/users/Gargron" {
+		t.Fatalf("unexpected actor url: %s", resolved.ActorURL)
+	}
+	if resolved.ProfileURL != "https://social.example.com/@Gargron" {
+		t.Fatalf("unexpected profile url: %s", resolved.ProfileURL)
+	}
+	if resolved.Username != "Gargron" || resolved.Domain != "example.com" {
+		t.Fatalf("unexpected handle: %s %s", resolved.Username, resolved.Domain)
+	}
+	if resolved.Client.Server != "https://social.example.com" {
+		t.Fatalf("client should use the server of the actor, got %s", resolved.Client.Server)
+	}
+	if resolved.Account.ID == "" {
+		t.Fatalf("account should be looked up")
+	}
+
+	_, err = ResolveHandle("@nobody@example.com", WithTransport(&rewriteTransport{target: target}))
+	if !IsNotFound(err) {
+		t.Fatalf("should be not found: %v", err)
+	}
+}
@@ -0,0 +1,515 @@
+package mastodon
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Interval between pings sent to keep the websocket alive
+	WebSocketPingInterval = 30 * time.Second
+	// Connections that received nothing for this long are reconnected
+	WebSocketReadTimeout = 2 * WebSocketPingInterval
+
+	// GUID used to compute Sec-WebSocket-Accept (RFC 6455)
+	webSocketGUID string = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	wsOpContinuation byte = 0x0
+	wsOpText         byte = 0x1
+	wsOpBinary       byte = 0x2
+	wsOpClose        byte = 0x8
+	wsOpPing         byte = 0x9
+	wsOpPong         byte = 0xa
+)
+
+// WebSocketStream carries several streams over a single websocket connection.
+// Subscriptions are sent again after every reconnect
+type WebSocketStream struct {
+	client   *Client
+	endpoint string
+	events   chan Event
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// Protects the subscriptions, the connection and writes to it
+	mu            sync.Mutex
+	subscriptions map[string]Stream
+	conn          *wsConn
+}
+
+// Message sent by the server for every event
+type webSocketMessage struct {
+	Stream  []string `json:"stream"`
+	Event   string   `json:"event"`
+	Payload string   `json:"payload"`
+}
+
+// Message sent to the server to change the subscriptions
+type webSocketCommand struct {
+	Type   string `json:"type"`
+	Stream string `json:"stream"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// StreamWebSocket opens a websocket to the streaming server. Use Subscribe to
+// add public or hashtag streams and Events to receive their events. Connection
+// errors are delivered as ErrorEvent and the websocket reconnects until the
+// context is done or Close is called, which closes the events channel
+func (c *Client) StreamWebSocket(ctx context.Context) (*WebSocketStream, error) {
+	if err := c.require(FeatureStreamingMultiplex); err != nil {
+		return nil, err
+	}
+
+	base, err := c.streamingURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &WebSocketStream{
+		client:        c,
+		endpoint:      base + StreamingURI,
+		events:        make(chan Event),
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		subscriptions: map[string]Stream{},
+	}
+
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// Events returns the channel on which the events of all subscriptions are delivered
+func (s *WebSocketStream) Events() <-chan Event {
+	return s.events
+}
+
+// Subscribe adds the stream to the connection
+func (s *WebSocketStream) Subscribe(stream Stream) error {
+	if err := stream.validate(); err != nil {
+		return err
+	}
+	stream.Tag = strings.TrimPrefix(stream.Tag, "#")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[streamKey(stream)] = stream
+	if s.conn == nil {
+		// Sent once connected
+		return nil
+	}
+
+	return s.send("subscribe", stream)
+}
+
+// Unsubscribe removes the stream from the connection
+func (s *WebSocketStream) Unsubscribe(stream Stream) error {
+	stream.Tag = strings.TrimPrefix(stream.Tag, "#")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscriptions, streamKey(stream))
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.send("unsubscribe", stream)
+}
+
+// Close closes the connection and stops reconnecting
+func (s *WebSocketStream) Close() error {
+	s.cancel()
+	<-s.done
+
+	return nil
+}
+
+// Get the key of a subscription
+func streamKey(stream Stream) string {
+	return stream.Name + "#" + stream.Tag
+}
+
+// Send a subscription command, must be called with the lock held
+func (s *WebSocketStream) send(command string, stream Stream) error {
+	data, err := json.Marshal(webSocketCommand{Type: command, Stream: stream.Name, Tag: stream.Tag})
+	if err != nil {
+		return err
+	}
+
+	return s.conn.writeFrame(wsOpText, data)
+}
+
+// Connect, read and reconnect until the context is done
+func (s *WebSocketStream) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.events)
+
+	delay := StreamReconnectDelay
+	for {
+		connected, err := s.connectAndRead(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = StreamReconnectDelay
+		}
+		if err == nil {
+			err = errors.New("websocket closed by the server")
+		}
+
+		select {
+		case s.events <- &ErrorEvent{Err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		delay, err = reconnectDelay(ctx, delay)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Open the connection, send the subscriptions and read messages until the
+// connection fails. Reports whether the connection was established
+func (s *WebSocketStream) connectAndRead(ctx context.Context) (bool, error) {
+	conn, err := s.client.dialWebSocket(ctx, s.endpoint)
+	if err != nil {
+		return false, err
+	}
+
+	// Close the connection when the context is done or it stops responding
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.heartbeat(ctx, conn, stop)
+
+	s.mu.Lock()
+	s.conn = conn
+	for _, stream := range s.subscriptions {
+		if err := s.send("subscribe", stream); err != nil {
+			s.conn = nil
+			s.mu.Unlock()
+			conn.Close()
+			return true, err
+		}
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		data, err := conn.readMessage(func(op byte, payload []byte) error {
+			// Answer pings while holding the write lock
+			if op == wsOpPing {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				return conn.writeFrame(wsOpPong, payload)
+			}
+			return nil
+		})
+		if err != nil {
+			return true, err
+		}
+
+		message := webSocketMessage{}
+		if err := json.Unmarshal(data, &message); err != nil {
+			continue
+		}
+		if message.Event == "" {
+			// Errors about subscriptions are sent without an event
+			message.Event = "error"
+			message.Payload = string(data)
+		}
+
+		event, err := decodeEvent(message.Event, message.Payload, message.Stream)
+		if err != nil {
+			event = &ErrorEvent{Err: fmt.Errorf("decoding %s event: %w", message.Event, err)}
+		} else if message.Event == "error" {
+			event = &ErrorEvent{Err: fmt.Errorf("streaming server: %s", message.Payload)}
+		}
+
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+	}
+}
+
+// Send pings and close the connection when nothing has been read for too
+// long or the context is done
+func (s *WebSocketStream) heartbeat(ctx context.Context, conn *wsConn, stop <-chan struct{}) {
+	ticker := time.NewTicker(WebSocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			conn.Close()
+			return
+		case <-ticker.C:
+			if time.Since(conn.lastRead()) > WebSocketReadTimeout {
+				conn.Close()
+				return
+			}
+
+			s.mu.Lock()
+			err := conn.writeFrame(wsOpPing, nil)
+			s.mu.Unlock()
+			if err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// Open a websocket using the http client, which keeps the transport, proxy
+// and headers of the client
+func (c *Client) dialWebSocket(ctx context.Context, endpoint string) (*wsConn, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	for k, values := range c.headers {
+		req.Header[k] = append([]string(nil), values...)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	resp, err := c.streamingClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return nil, newAPIError(endpoint, resp)
+	}
+
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, errors.New("transport does not support websocket upgrades")
+	}
+
+	if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		rwc.Close()
+		return nil, errors.New("invalid Sec-WebSocket-Accept header")
+	}
+
+	return newWSConn(rwc, true), nil
+}
+
+// Compute the Sec-WebSocket-Accept value for the key
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Minimal websocket connection that reads and writes frames (RFC 6455)
+type wsConn struct {
+	rwc    io.ReadWriteCloser
+	reader *bufio.Reader
+	// Clients mask the frames they send, servers do not
+	mask bool
+
+	closeOnce sync.Once
+	readMu    sync.Mutex
+	lastread  time.Time
+}
+
+// Wrap the connection
+func newWSConn(rwc io.ReadWriteCloser, mask bool) *wsConn {
+	return &wsConn{
+		rwc:      rwc,
+		reader:   bufio.NewReader(rwc),
+		mask:     mask,
+		lastread: time.Now(),
+	}
+}
+
+// Close the underlying connection
+func (w *wsConn) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		err = w.rwc.Close()
+	})
+
+	return err
+}
+
+// Get the time a frame was last read
+func (w *wsConn) lastRead() time.Time {
+	w.readMu.Lock()
+	defer w.readMu.Unlock()
+
+	return w.lastread
+}
+
+// Write a single frame. Writes must not be concurrent
+func (w *wsConn) writeFrame(op byte, payload []byte) error {
+	header := []byte{0x80 | op, 0}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	data := payload
+	if w.mask {
+		header[1] |= 0x80
+		key := make([]byte, 4)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		header = append(header, key...)
+
+		data = make([]byte, length)
+		for i := range payload {
+			data[i] = payload[i] ^ key[i%4]
+		}
+	}
+
+	_, err := w.rwc.Write(append(header, data...))
+
+	return err
+}
+
+// Read a single frame
+func (w *wsConn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(w.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	op := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(w.reader, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(w.reader, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > maxStreamEventSize {
+		return false, 0, nil, fmt.Errorf("websocket frame of %d bytes is too large", length)
+	}
+
+	var key []byte
+	if masked {
+		key = make([]byte, 4)
+		if _, err := io.ReadFull(w.reader, key); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(w.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+
+	w.readMu.Lock()
+	w.lastread = time.Now()
+	w.readMu.Unlock()
+
+	return fin, op, payload, nil
+}
+
+// Read the next text or binary message, joining fragmented frames. Control
+// frames are passed to the handler, a close frame ends the connection
+func (w *wsConn) readMessage(control func(op byte, payload []byte) error) ([]byte, error) {
+	var message []byte
+	started := false
+
+	for {
+		fin, op, payload, err := w.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case wsOpClose:
+			return nil, errors.New("websocket closed by the server")
+		case wsOpPing, wsOpPong:
+			if err := control(op, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpText, wsOpBinary:
+			message = payload
+			started = true
+		case wsOpContinuation:
+			if !started {
+				return nil, errors.New("unexpected websocket continuation frame")
+			}
+			message = append(message, payload...)
+		default:
+			return nil, fmt.Errorf("unknown websocket opcode %d", op)
+		}
+
+		if len(message) > maxStreamEventSize {
+			return nil, errors.New("websocket message is too large")
+		}
+
+		if fin {
+			return message, nil
+		}
+	}
+}
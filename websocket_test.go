@@ -0,0 +1,168 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamWebSocket(t *testing.T) {
+	// Setup Status
+	var statuses Statuses
+	err := json.Unmarshal([]byte(testtrendsstatuses), &statuses)
+	if err != nil {
+		t.Fatalf("error unmarshalling test statuses: %v", err)
+	}
+	status, err := json.Marshal(statuses[0])
+	if err != nil {
+		t.Fatalf("error marshalling test status: %v", err)
+	}
+
+	var mu sync.Mutex
+	connections := 0
+	commands := []string{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != StreamingURI || r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		// Accept the websocket
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("server does not support hijacking")
+			return
+		}
+		netconn, bufrw, err := hj.Hijack()
+		if err != nil {
+			t.Errorf("failed to hijack connection: %v", err)
+			return
+		}
+		defer netconn.Close()
+
+		fmt.Fprintf(bufrw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", webSocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+		bufrw.Flush()
+
+		mu.Lock()
+		connections++
+		connection := connections
+		mu.Unlock()
+
+		conn := newWSConn(netconn, false)
+		conn.reader = bufrw.Reader
+
+		// Answer every subscription with an event for that stream
+		for {
+			data, err := conn.readMessage(func(op byte, payload []byte) error { return nil })
+			if err != nil {
+				return
+			}
+
+			var command webSocketCommand
+			if err := json.Unmarshal(data, &command); err != nil {
+				t.Errorf("invalid command: %s", data)
+				return
+			}
+
+			mu.Lock()
+			commands = append(commands, fmt.Sprintf("%d:%s:%s:%s", connection, command.Type, command.Stream, command.Tag))
+			mu.Unlock()
+
+			if command.Type != "subscribe" {
+				continue
+			}
+
+			stream := []string{command.Stream}
+			if command.Tag != "" {
+				stream = append(stream, command.Tag)
+			}
+			message, _ := json.Marshal(webSocketMessage{Stream: stream, Event: "update", Payload: string(status)})
+			conn.writeFrame(wsOpPing, []byte("ping"))
+
+			// Send the message in two fragments
+			half := len(message) / 2
+			bufrw.Write([]byte{wsOpText, 126, byte(half >> 8), byte(half)})
+			bufrw.Write(message[:half])
+			rest := len(message) - half
+			bufrw.Write([]byte{0x80 | wsOpContinuation, 126, byte(rest >> 8), byte(rest)})
+			bufrw.Write(message[half:])
+			bufrw.Flush()
+
+			// The first connection drops after the hashtag subscription
+			if connection == 1 && command.Stream == StreamHashtag {
+				message, _ = json.Marshal(webSocketMessage{Stream: stream, Event: "delete", Payload: "1"})
+				conn.writeFrame(wsOpText, message)
+				conn.writeFrame(wsOpClose, nil)
+				return
+			}
+		}
+	}))
+	defer ts.Close()
+
+	// Setup client
+	client, err := NewClient(ts.URL, WithStreamingURL(strings.Replace(ts.URL, "http://", "ws://", 1)))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, err := client.StreamWebSocket(ctx)
+	if err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	if err := ws.Subscribe(Stream{Name: StreamHashtag}); err == nil {
+		t.Fatalf("should fail for a hashtag stream without a tag")
+	}
+	if err := ws.Subscribe(Stream{Name: StreamPublicLocal}); err != nil {
+		t.Fatalf("should not be fail: %v", err)
+	}
+
+	received := []string{}
+	for event := range ws.Events() {
+		switch e := event.(type) {
+		case *UpdateEvent:
+			received = append(received, "update:"+strings.Join(e.Stream, ":"))
+			if e.Status.ID != "108910940413327534" {
+				t.Fatalf("update was incorrectly decoded: %+v", e)
+			}
+		case *DeleteEvent:
+			received = append(received, "delete:"+e.ID)
+		case *ErrorEvent:
+			received = append(received, "error")
+		}
+
+		switch len(received) {
+		case 1:
+			if err := ws.Subscribe(Stream{Name: StreamHashtag, Tag: "#mastodon"}); err != nil {
+				t.Fatalf("should not be fail: %v", err)
+			}
+		case 6:
+			// Both streams were resubscribed after the reconnect
+			ws.Close()
+		}
+	}
+
+	if len(received) != 6 {
+		t.Fatalf("received the wrong events: %v", received)
+	}
+	expected := "update:public:local,update:hashtag:mastodon,delete:1,error"
+	if strings.Join(received[:4], ",") != expected {
+		t.Fatalf("received the wrong events: %v", received)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 2 || len(commands) != 4 {
+		t.Fatalf("should have resubscribed both streams after reconnecting: %v", commands)
+	}
+}